@@ -2,9 +2,16 @@ package entc
 
 import (
 	"embed"
+	"fmt"
+	"slices"
+	"text/template"
+	"time"
 
+	"entgo.io/contrib/entoas"
 	"entgo.io/ent/entc"
 	"entgo.io/ent/entc/gen"
+
+	"github.com/eidng8/go-ent/softdelete"
 )
 
 //go:embed templates/*/*.tmpl
@@ -37,3 +44,224 @@ func (*SimpleTreeExtension) Templates() []*gen.Template {
 		),
 	}
 }
+
+// PaginateExtension generates the typed `Paginate()` method on every query
+// builder.
+type PaginateExtension struct {
+	entc.DefaultExtension
+}
+
+func (*PaginateExtension) Templates() []*gen.Template {
+	return []*gen.Template{
+		gen.MustParse(
+			gen.NewTemplate("query_paginate").
+				ParseFS(tmpldir, "templates/paginate/*.tmpl"),
+		),
+	}
+}
+
+// GinHandlerExtension generates gin handlers of the list operation of every
+// entity. It requires PaginateExtension.
+type GinHandlerExtension struct {
+	entc.DefaultExtension
+}
+
+func (*GinHandlerExtension) Templates() []*gen.Template {
+	return []*gen.Template{
+		gen.MustParse(
+			gen.NewTemplate("gin_handler").
+				Funcs(template.FuncMap{"hasListOperation": hasListOperation}).
+				ParseFS(tmpldir, "templates/gin/*.tmpl"),
+		),
+	}
+}
+
+// hasListOperation tells whether entoas exposes the list operation of the
+// type, honoring `entoas.Skip()` and operation policies. Every type has it
+// if the entoas extension is not used.
+func hasListOperation(t *gen.Type) (bool, error) {
+	if _, ok := t.Config.Annotations[entoas.Config{}.Name()]; !ok {
+		return true, nil
+	}
+	a, err := entoas.SchemaAnnotation(t)
+	if err != nil {
+		return false, err
+	}
+	if a.Skip {
+		return false, nil
+	}
+	ops, err := entoas.NodeOperations(t)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(ops, entoas.OpList), nil
+}
+
+// SoftDeleteExtension generates the cascading soft delete of every entity
+// having the "deletion_id" field of softdelete.DeletionIDMixin, and the purge
+// targets of entities annotated by `softdelete.Retention()`. It requires
+// ClientExtension.
+type SoftDeleteExtension struct {
+	entc.DefaultExtension
+}
+
+func (*SoftDeleteExtension) Templates() []*gen.Template {
+	return []*gen.Template{
+		gen.MustParse(
+			gen.NewTemplate("softdelete_cascade").
+				Funcs(
+					template.FuncMap{
+						"hasDeletionID": hasDeletionID,
+						"cascadeEdges":  cascadeEdges,
+						"purgeNodes":    purgeNodes,
+					},
+				).
+				ParseFS(tmpldir, "templates/softdelete/*.tmpl"),
+		),
+	}
+}
+
+// hasDeletionID tells whether the type has both the "deleted_at" and the
+// "deletion_id" fields.
+func hasDeletionID(t *gen.Type) bool {
+	return hasField(t, softdelete.FieldDeletedAt) &&
+		hasField(t, softdelete.FieldDeletionID)
+}
+
+// hasField tells whether the type has the field of the given name.
+func hasField(t *gen.Type, name string) bool {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// cascadeEdges returns edges of the type annotated by `softdelete.Cascade()`.
+// It fails if the target of any of them doesn't have the "deletion_id" field.
+func cascadeEdges(t *gen.Type) ([]*gen.Edge, error) {
+	var edges []*gen.Edge
+	for _, e := range t.Edges {
+		a, ok := e.Annotations[softdelete.CascadeAnnotation{}.Name()].(map[string]any)
+		if !ok || true != a["enabled"] {
+			continue
+		}
+		if !hasDeletionID(t) || !hasDeletionID(e.Type) {
+			return nil, fmt.Errorf(
+				"soft delete cascade of %s.%s requires %s and %s to use "+
+					"softdelete.Mixin and softdelete.DeletionIDMixin",
+				t.Name, e.Name, t.Name, e.Type.Name,
+			)
+		}
+		edges = append(edges, e)
+	}
+	return edges, nil
+}
+
+// purgeNode is a type having a retention period.
+type purgeNode struct {
+	*gen.Type
+	// Retention is the retention period of trashed rows.
+	Retention time.Duration
+}
+
+// RetentionExpr returns the Go expression of the retention period.
+func (n purgeNode) RetentionExpr() string {
+	for _, unit := range []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+	} {
+		if 0 == n.Retention%unit.d {
+			return fmt.Sprintf("%d * %s", n.Retention/unit.d, unit.name)
+		}
+	}
+	return fmt.Sprintf("%d", n.Retention)
+}
+
+// purgeNodes returns types annotated by `softdelete.Retention()`. Types
+// referencing others by foreign keys come before the referenced ones.
+func purgeNodes(g *gen.Graph) ([]purgeNode, error) {
+	var nodes []purgeNode
+	for _, t := range g.Nodes {
+		a, ok := t.Annotations[softdelete.RetentionAnnotation{}.Name()].(map[string]any)
+		if !ok {
+			continue
+		}
+		period, _ := a["period"].(string)
+		retention, err := time.ParseDuration(period)
+		if err != nil {
+			return nil, fmt.Errorf("retention of %s: %w", t.Name, err)
+		}
+		if retention <= 0 {
+			return nil, fmt.Errorf(
+				"retention of %s must be positive, got %s", t.Name, retention,
+			)
+		}
+		if !t.HasOneFieldID() || !hasField(t, softdelete.FieldDeletedAt) {
+			return nil, fmt.Errorf(
+				"retention of %s requires a single ID field and "+
+					"softdelete.Mixin", t.Name,
+			)
+		}
+		nodes = append(nodes, purgeNode{Type: t, Retention: retention})
+	}
+	return sortByReferences(nodes), nil
+}
+
+// sortByReferences sorts nodes so that referencing nodes come first, keeping
+// the original order otherwise. Nodes of reference cycles are kept in the
+// original order.
+func sortByReferences(nodes []purgeNode) []purgeNode {
+	// referencedBy counts nodes referencing each node
+	referencedBy := make(map[*gen.Type]int, len(nodes))
+	for _, n := range nodes {
+		for _, ref := range references(n.Type) {
+			referencedBy[ref]++
+		}
+	}
+	sorted := make([]purgeNode, 0, len(nodes))
+	done := make(map[*gen.Type]bool, len(nodes))
+	for len(sorted) < len(nodes) {
+		progress := false
+		for _, n := range nodes {
+			if done[n.Type] || referencedBy[n.Type] > 0 {
+				continue
+			}
+			done[n.Type], progress = true, true
+			sorted = append(sorted, n)
+			for _, ref := range references(n.Type) {
+				referencedBy[ref]--
+			}
+		}
+		if progress {
+			continue
+		}
+		for _, n := range nodes {
+			if !done[n.Type] {
+				done[n.Type] = true
+				sorted = append(sorted, n)
+			}
+		}
+	}
+	return sorted
+}
+
+// references returns other types referenced by foreign keys of the type.
+func references(t *gen.Type) []*gen.Type {
+	var refs []*gen.Type
+	for _, fk := range t.ForeignKeys {
+		ref := fk.Edge.Type
+		if ref == t {
+			ref = fk.Edge.Owner
+		}
+		if ref != t {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,132 @@
+package entc
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"entgo.io/ent/entc/gen"
+)
+
+// refer adds the foreign key of `from` referencing `to`. `owned` tells
+// whether the edge is declared by `to`, e.g. the O2M `users.pets` edge, whose
+// foreign key is in the `pets` table.
+func refer(from, to *gen.Type, owned bool) {
+	e := &gen.Edge{Owner: from, Type: to}
+	if owned {
+		e = &gen.Edge{Owner: to, Type: from}
+	}
+	from.ForeignKeys = append(from.ForeignKeys, &gen.ForeignKey{Edge: e})
+}
+
+func TestSortByReferences(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(types map[string]*gen.Type) []string
+		want  []string
+	}{
+		{
+			"no reference",
+			func(map[string]*gen.Type) []string {
+				return []string{"a", "b", "c"}
+			},
+			[]string{"a", "b", "c"},
+		},
+		{
+			"referencing first",
+			func(ts map[string]*gen.Type) []string {
+				refer(ts["pets"], ts["users"], true)
+				return []string{"users", "pets"}
+			},
+			[]string{"pets", "users"},
+		},
+		{
+			"chain",
+			func(ts map[string]*gen.Type) []string {
+				refer(ts["comments"], ts["posts"], false)
+				refer(ts["posts"], ts["users"], true)
+				return []string{"users", "posts", "comments"}
+			},
+			[]string{"comments", "posts", "users"},
+		},
+		{
+			"shared reference",
+			func(ts map[string]*gen.Type) []string {
+				refer(ts["pets"], ts["users"], true)
+				refer(ts["posts"], ts["users"], false)
+				return []string{"users", "pets", "posts"}
+			},
+			[]string{"pets", "posts", "users"},
+		},
+		{
+			"self reference",
+			func(ts map[string]*gen.Type) []string {
+				refer(ts["nodes"], ts["nodes"], false)
+				refer(ts["leaves"], ts["nodes"], false)
+				return []string{"nodes", "leaves"}
+			},
+			[]string{"leaves", "nodes"},
+		},
+		{
+			"cycle kept in order",
+			func(ts map[string]*gen.Type) []string {
+				refer(ts["a"], ts["b"], false)
+				refer(ts["b"], ts["a"], false)
+				return []string{"a", "b", "c"}
+			},
+			[]string{"c", "a", "b"},
+		},
+		{
+			"reference to type not purged",
+			func(ts map[string]*gen.Type) []string {
+				refer(ts["pets"], ts["users"], true)
+				return []string{"pets", "tags"}
+			},
+			[]string{"pets", "tags"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				types := make(map[string]*gen.Type)
+				for _, name := range []string{
+					"a", "b", "c", "users", "pets", "posts", "comments",
+					"nodes", "leaves", "tags",
+				} {
+					types[name] = &gen.Type{Name: name}
+				}
+				var nodes []purgeNode
+				for _, name := range tt.setup(types) {
+					nodes = append(
+						nodes, purgeNode{Type: types[name], Retention: time.Hour},
+					)
+				}
+				var got []string
+				for _, n := range sortByReferences(nodes) {
+					got = append(got, n.Name)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestRetentionExpr(t *testing.T) {
+	tests := []struct {
+		retention time.Duration
+		want      string
+	}{
+		{30 * 24 * time.Hour, "720 * time.Hour"},
+		{90 * time.Minute, "90 * time.Minute"},
+		{time.Second, "1 * time.Second"},
+		{1500 * time.Millisecond, "1500000000"},
+	}
+	for _, tt := range tests {
+		got := purgeNode{Retention: tt.retention}.RetentionExpr()
+		if got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+}
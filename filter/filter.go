@@ -0,0 +1,258 @@
+package filter
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/go-faster/errors"
+
+	"github.com/eidng8/go-ent/paginate"
+)
+
+// Op is a filter operator, given in brackets after the field name of a query
+// parameter, e.g. `name[contains]=x`. A parameter without operator, e.g.
+// `name=x`, uses OpEq.
+type Op string
+
+const (
+	// OpEq matches values equal to the given value.
+	OpEq Op = "eq"
+	// OpNeq matches values not equal to the given value.
+	OpNeq Op = "neq"
+	// OpIn matches values in the given comma separated list.
+	OpIn Op = "in"
+	// OpContains matches string values containing the given value.
+	OpContains Op = "contains"
+	// OpGt matches values greater than the given value.
+	OpGt Op = "gt"
+	// OpGte matches values greater than or equal to the given value.
+	OpGte Op = "gte"
+	// OpLt matches values less than the given value.
+	OpLt Op = "lt"
+	// OpLte matches values less than or equal to the given value.
+	OpLte Op = "lte"
+	// OpIsNull matches null values if the given value is `true`, or non-null
+	// values if it is `false`.
+	OpIsNull Op = "isnull"
+)
+
+// Type is the value type of a filterable field.
+type Type int
+
+const (
+	// String values are used as is.
+	String Type = iota
+	// Int values are parsed as 64-bit integers.
+	Int
+	// Float values are parsed as 64-bit floating point numbers.
+	Float
+	// Bool values are parsed by strconv.ParseBool().
+	Bool
+	// Time values are parsed in RFC 3339 format.
+	Time
+)
+
+// Field declares a filterable field.
+type Field struct {
+	// Name is the field name used in query parameters.
+	Name string
+	// Column is the database column name. Defaults to Name.
+	Column string
+	// Type is the value type of the field.
+	Type Type
+	// Ops is the list of allowed operators.
+	Ops []Op
+}
+
+// Filter is a parsed filter parameter.
+type Filter struct {
+	// Field is the filtered field.
+	Field Field
+	// Op is the filter operator.
+	Op Op
+	// Values holds the parsed values. It has exactly one value, except for
+	// OpIn, which has at least one.
+	Values []any
+}
+
+// Querier is an interface that defines the method to apply storage-level
+// predicates. Both `paginate.SQ` and the generated `intercept.Query` satisfy
+// this interface.
+type Querier interface {
+	WhereP(...func(*sql.Selector))
+}
+
+var paramPattern = regexp.MustCompile(`^([^\[\]]+)(?:\[([^\[\]]+)])?$`)
+
+// Apply parses filter parameters from the given query values, and applies
+// them to the query. A *paginate.ValidationError is returned if any filter
+// parameter is invalid, in which case nothing is applied.
+func Apply(query Querier, values url.Values, fields []Field) error {
+	filters, err := Parse(values, fields)
+	if err != nil {
+		return err
+	}
+	ps := make([]func(*sql.Selector), len(filters))
+	for i, filter := range filters {
+		ps[i] = filter.Predicate()
+	}
+	if len(ps) > 0 {
+		query.WhereP(ps...)
+	}
+	return nil
+}
+
+// Parse parses filter parameters from the given query values. Parameters not
+// referring to any of the given fields are ignored. A
+// *paginate.ValidationError is returned if any filter parameter is invalid.
+func Parse(values url.Values, fields []Field) ([]Filter, error) {
+	var verr paginate.ValidationError
+	var filters []Filter
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		m := paramPattern.FindStringSubmatch(name)
+		if nil == m {
+			continue
+		}
+		idx := slices.IndexFunc(
+			fields, func(f Field) bool { return f.Name == m[1] },
+		)
+		if idx < 0 {
+			continue
+		}
+		field := fields[idx]
+		op := OpEq
+		if "" != m[2] {
+			op = Op(m[2])
+		}
+		for _, value := range values[name] {
+			if !slices.Contains(field.Ops, op) {
+				verr.Add(name, value, "operator is not allowed")
+				continue
+			}
+			parsed, err := parseValues(field, op, value)
+			if err != nil {
+				verr.Add(name, value, err.Error())
+				continue
+			}
+			filters = append(
+				filters, Filter{Field: field, Op: op, Values: parsed},
+			)
+		}
+	}
+	if len(verr.Params) > 0 {
+		return nil, &verr
+	}
+	return filters, nil
+}
+
+// Predicate returns the storage-level predicate of the filter.
+func (f Filter) Predicate() func(*sql.Selector) {
+	column := f.Field.column()
+	switch f.Op {
+	case OpNeq:
+		return sql.FieldNEQ(column, f.Values[0])
+	case OpIn:
+		return sql.FieldIn(column, f.Values...)
+	case OpContains:
+		return sql.FieldContains(column, f.Values[0].(string))
+	case OpGt:
+		return sql.FieldGT(column, f.Values[0])
+	case OpGte:
+		return sql.FieldGTE(column, f.Values[0])
+	case OpLt:
+		return sql.FieldLT(column, f.Values[0])
+	case OpLte:
+		return sql.FieldLTE(column, f.Values[0])
+	case OpIsNull:
+		if f.Values[0].(bool) {
+			return sql.FieldIsNull(column)
+		}
+		return sql.FieldNotNull(column)
+	default:
+		return sql.FieldEQ(column, f.Values[0])
+	}
+}
+
+func (f Field) column() string {
+	if "" == f.Column {
+		return f.Name
+	}
+	return f.Column
+}
+
+// parseValues parses the raw parameter value according to the field type
+// and operator.
+func parseValues(field Field, op Op, raw string) ([]any, error) {
+	switch op {
+	case OpIsNull:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("must be a boolean")
+		}
+		return []any{b}, nil
+	case OpContains:
+		if String != field.Type {
+			return nil, errors.New("operator only applies to strings")
+		}
+		return []any{raw}, nil
+	case OpIn:
+		parts := strings.Split(raw, ",")
+		values := make([]any, len(parts))
+		for i, part := range parts {
+			value, err := parseValue(field.Type, part)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	case OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte:
+		value, err := parseValue(field.Type, raw)
+		if err != nil {
+			return nil, err
+		}
+		return []any{value}, nil
+	}
+	return nil, errors.New("unknown operator")
+}
+
+func parseValue(typ Type, raw string) (any, error) {
+	switch typ {
+	case Int:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, errors.New("must be an integer")
+		}
+		return v, nil
+	case Float:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		return v, nil
+	case Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("must be a boolean")
+		}
+		return v, nil
+	case Time:
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, errors.New("must be an RFC 3339 date-time")
+		}
+		return v, nil
+	default:
+		return raw, nil
+	}
+}
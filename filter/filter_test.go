@@ -0,0 +1,159 @@
+package filter
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+
+	"github.com/eidng8/go-ent/paginate"
+)
+
+var testFields = []Field{
+	{Name: "name", Type: String, Ops: []Op{OpEq, OpNeq, OpContains, OpIn}},
+	{Name: "age", Type: Int, Ops: []Op{OpEq, OpGt, OpLte, OpIn}},
+	{Name: "score", Type: Float, Ops: []Op{OpGte}},
+	{Name: "active", Type: Bool, Ops: []Op{OpEq}},
+	{Name: "created", Column: "created_at", Type: Time, Ops: []Op{OpLt, OpIsNull}},
+}
+
+func TestParse(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name  string
+		query string
+		want  []Filter
+	}{
+		{"none", "", nil},
+		{"unknown field ignored", "page=2&other[eq]=1", nil},
+		{
+			"default operator", "name=a",
+			[]Filter{{Field: testFields[0], Op: OpEq, Values: []any{"a"}}},
+		},
+		{
+			"explicit operator", "name[neq]=a",
+			[]Filter{{Field: testFields[0], Op: OpNeq, Values: []any{"a"}}},
+		},
+		{
+			"contains", "name[contains]=a",
+			[]Filter{{Field: testFields[0], Op: OpContains, Values: []any{"a"}}},
+		},
+		{
+			"string list", "name[in]=a,b",
+			[]Filter{{Field: testFields[0], Op: OpIn, Values: []any{"a", "b"}}},
+		},
+		{
+			"int list", "age[in]=1,2",
+			[]Filter{{Field: testFields[1], Op: OpIn, Values: []any{int64(1), int64(2)}}},
+		},
+		{
+			"repeated parameter", "age[gt]=1&age[lte]=9",
+			[]Filter{
+				{Field: testFields[1], Op: OpGt, Values: []any{int64(1)}},
+				{Field: testFields[1], Op: OpLte, Values: []any{int64(9)}},
+			},
+		},
+		{
+			"float", "score[gte]=1.5",
+			[]Filter{{Field: testFields[2], Op: OpGte, Values: []any{1.5}}},
+		},
+		{
+			"bool", "active=true",
+			[]Filter{{Field: testFields[3], Op: OpEq, Values: []any{true}}},
+		},
+		{
+			"time", "created[lt]=2024-01-02T03:04:05Z",
+			[]Filter{{Field: testFields[4], Op: OpLt, Values: []any{created}}},
+		},
+		{
+			"is null", "created[isnull]=false",
+			[]Filter{{Field: testFields[4], Op: OpIsNull, Values: []any{false}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				values, _ := url.ParseQuery(tt.query)
+				got, err := Parse(values, testFields)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("got %+v, want %+v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"operator not allowed", "name[gt]=a", []string{"name[gt]"}},
+		{"unknown operator", "age[like]=1", []string{"age[like]"}},
+		{"not an integer", "age=x", []string{"age"}},
+		{"not an integer in list", "age[in]=1,x", []string{"age[in]"}},
+		{"not a number", "score[gte]=x", []string{"score[gte]"}},
+		{"not a boolean", "active=x", []string{"active"}},
+		{"not a date-time", "created[lt]=2024-01-02", []string{"created[lt]"}},
+		{"is null not a boolean", "created[isnull]=x", []string{"created[isnull]"}},
+		{"every invalid one", "age=x&name[gt]=a&name=b", []string{"age", "name[gt]"}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				values, _ := url.ParseQuery(tt.query)
+				_, err := Parse(values, testFields)
+				var verr *paginate.ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("want *paginate.ValidationError, got %v", err)
+				}
+				var got []string
+				for _, param := range verr.Params {
+					got = append(got, param.Name)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestFilterPredicate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{
+			"column", Filter{Field: testFields[4], Op: OpIsNull, Values: []any{true}},
+			"SELECT * FROM `t` WHERE `t`.`created_at` IS NULL",
+		},
+		{
+			"in", Filter{Field: testFields[1], Op: OpIn, Values: []any{1, 2}},
+			"SELECT * FROM `t` WHERE `t`.`age` IN (?, ?)",
+		},
+		{
+			"contains", Filter{Field: testFields[0], Op: OpContains, Values: []any{"a"}},
+			"SELECT * FROM `t` WHERE `t`.`name` LIKE ?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				s := sql.Select("*").From(sql.Table("t"))
+				tt.filter.Predicate()(s)
+				if query, _ := s.Query(); query != tt.want {
+					t.Errorf("got %s, want %s", query, tt.want)
+				}
+			},
+		)
+	}
+}
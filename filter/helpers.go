@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/ogen-go/ogen"
+)
+
+// AttachTo adds filter parameters of the given fields to the OpenAPI
+// operation.
+func AttachTo(op *ogen.Operation, fields ...Field) {
+	op.AddParameters(Params(fields...)...)
+}
+
+// Params returns query parameters of the given fields, one for each allowed
+// operator. OpEq is also added as a parameter without brackets.
+func Params(fields ...Field) []*ogen.Parameter {
+	var params []*ogen.Parameter
+	for _, field := range fields {
+		for _, op := range field.Ops {
+			if OpEq == op {
+				params = append(params, param(field.Name, field, op))
+			}
+			params = append(
+				params, param(fmt.Sprintf("%s[%s]", field.Name, op), field, op),
+			)
+		}
+	}
+	return params
+}
+
+func param(name string, field Field, op Op) *ogen.Parameter {
+	var schema *ogen.Schema
+	var description string
+	switch op {
+	case OpIsNull:
+		schema = &ogen.Schema{Type: "boolean"}
+		description = fmt.Sprintf("Whether `%s` is null", field.Name)
+	case OpIn:
+		schema = &ogen.Schema{Type: "string"}
+		description = fmt.Sprintf(
+			"Comma separated values that `%s` is one of", field.Name,
+		)
+	default:
+		schema = valueSchema(field.Type)
+		description = fmt.Sprintf(
+			"Filter `%s` by the `%s` operator", field.Name, op,
+		)
+	}
+	return &ogen.Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Required:    false,
+		Schema:      schema,
+	}
+}
+
+func valueSchema(typ Type) *ogen.Schema {
+	switch typ {
+	case Int:
+		return &ogen.Schema{Type: "integer", Format: "int64"}
+	case Float:
+		return &ogen.Schema{Type: "number", Format: "double"}
+	case Bool:
+		return &ogen.Schema{Type: "boolean"}
+	case Time:
+		return &ogen.Schema{Type: "string", Format: "date-time"}
+	default:
+		return &ogen.Schema{Type: "string"}
+	}
+}
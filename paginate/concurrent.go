@@ -0,0 +1,86 @@
+package paginate
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// countAndFetch counts the total number of rows using `Counter`, and fetches
+// rows of the given window. It also tells whether the count is exact, and
+// the ETag of the page if `UpdatedAt` is set. Rows are not fetched if there
+// is exactly none in sequential mode. In concurrent mode, the count query
+// runs on a clone of the query, and the other query is canceled as soon as
+// either one fails.
+func (p Paginator[V, Q]) countAndFetch(offset, limit int) (
+	int, bool, string, []*V, error,
+) {
+	if !p.Concurrent || "" != p.UpdatedAt {
+		var etag string
+		count, exact, err := p.count(p.QueryCtx, p.Query)
+		if err != nil {
+			return 0, false, "", nil, err
+		}
+		if "" != p.UpdatedAt {
+			if etag, err = p.etag(count, offset, limit); err != nil {
+				return 0, false, etag, nil, err
+			}
+		}
+		if exact && 0 == count {
+			return count, exact, etag, nil, nil
+		}
+		p.Query.Offset(offset)
+		p.Query.Limit(limit)
+		rows, err := p.Query.All(p.QueryCtx)
+		return count, exact, etag, rows, err
+	}
+	cq, err := cloneQuery(p.Query)
+	if err != nil {
+		return 0, false, "", nil, err
+	}
+	var count int
+	var exact bool
+	var rows []*V
+	g, ctx := errgroup.WithContext(p.queryContext())
+	g.Go(
+		func() (err error) {
+			count, exact, err = p.count(ctx, cq)
+			return
+		},
+	)
+	p.Query.Offset(offset)
+	p.Query.Limit(limit)
+	g.Go(
+		func() (err error) {
+			rows, err = p.Query.All(ctx)
+			return
+		},
+	)
+	if err = g.Wait(); err != nil {
+		return 0, false, "", nil, err
+	}
+	return count, exact, "", rows, nil
+}
+
+// queryContext returns `QueryCtx`, or context.Background() if it is nil.
+func (p Paginator[V, Q]) queryContext() context.Context {
+	if nil == p.QueryCtx {
+		return context.Background()
+	}
+	return p.QueryCtx
+}
+
+// cloneQuery returns a deep copy of the given query, so that it can be used
+// in another goroutine.
+func cloneQuery[V any, Q any](query PQ[V, Q]) (PQ[V, Q], error) {
+	cloner, ok := query.(interface{ Clone() *Q })
+	if !ok {
+		return nil, errors.Errorf("query %T can't be cloned", query)
+	}
+	cq, ok := any(cloner.Clone()).(PQ[V, Q])
+	if !ok {
+		return nil, errors.Errorf("clone of query %T isn't a PQ", query)
+	}
+	return cq, nil
+}
@@ -0,0 +1,96 @@
+package paginate
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// PageRequest holds the request information needed by the pagination core.
+// It doesn't depend on any web framework, adapters are provided to create it
+// from gin.Context, http.Request and ogen generated servers.
+type PageRequest struct {
+	// Url is the URL of the incoming request. It is used to generate links
+	// of the paginated list, so query parameters other than pagination ones
+	// are retained in these links.
+	Url *url.URL
+	// Request is the incoming HTTP request, used by UrlBuilder to read the
+	// forwarded headers. It is optional.
+	Request *http.Request
+	// Params is the pagination parameters of the request.
+	Params PaginatedParams
+}
+
+// NewPageRequest returns the PageRequest of the given URL, with default
+// values of page `1` and `10` items per page.
+func NewPageRequest(u *url.URL) *PageRequest {
+	return &PageRequest{
+		Url:    u,
+		Params: GetPaginationParamsFromQuery(u.Query(), 1, 10),
+	}
+}
+
+// GetPaginationParamsFromQuery returns the PaginatedParams from the given
+// query values with given default values. Like the gin adapter, both default
+// values are used if any of the parameters is not a valid integer.
+func GetPaginationParamsFromQuery(
+	values url.Values, defaultPage, defaultPerPage int,
+) PaginatedParams {
+	var page PaginatedParams
+	var err error
+	page.Cursor = values.Get(ParamCursor)
+	if v := values.Get(ParamPage); "" != v {
+		page.Page, err = strconv.Atoi(v)
+	}
+	if v := values.Get(ParamPerPage); nil == err && "" != v {
+		page.PerPage, err = strconv.Atoi(v)
+	}
+	if err != nil {
+		page.Page = defaultPage
+		page.PerPage = defaultPerPage
+	}
+	if page.Page < 1 {
+		page.Page = defaultPage
+	}
+	if page.PerPage < 1 {
+		page.PerPage = defaultPerPage
+	}
+	return page
+}
+
+// pageRequest returns the request information to be used by the paginator.
+func (p Paginator[V, Q]) pageRequest() *PageRequest {
+	if nil != p.PageRequest {
+		return p.PageRequest
+	}
+	return NewGinPageRequest(p.GinCtx)
+}
+
+// requestUrl returns the URL of the incoming request.
+func (p Paginator[V, Q]) requestUrl() *url.URL {
+	if nil != p.PageRequest {
+		return p.PageRequest.Url
+	}
+	return p.GinCtx.Request.URL
+}
+
+// prepare applies request dependent clauses to the query before it is
+// executed.
+func (p Paginator[V, Q]) prepare() error {
+	if err := p.checkUrlBuilder(); err != nil {
+		return err
+	}
+	if err := p.applyFields(); err != nil {
+		return err
+	}
+	if err := p.applyIncludes(); err != nil {
+		return err
+	}
+	if err := p.applySort(); err != nil {
+		return err
+	}
+	if nil != p.DefaultOrder {
+		p.DefaultOrder()
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package paginate
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// Countable is an interface that defines the method for queries to be
+// counted. All PQ queries are Countable.
+type Countable interface {
+	Count(context.Context) (int, error)
+}
+
+// Counter is an interface that defines the strategy to count the total number
+// of items of a paginated list.
+type Counter interface {
+	// Count returns the total number of items of the query, and whether the
+	// total is exact. `key` identifies the query, which is the request URL
+	// without pagination parameters.
+	Count(ctx context.Context, key string, query Countable) (int, bool, error)
+}
+
+// CounterFunc is an adapter to allow the use of ordinary functions as Counter.
+type CounterFunc func(
+	ctx context.Context, key string, query Countable,
+) (int, bool, error)
+
+// Count calls f(ctx, key, query).
+func (f CounterFunc) Count(
+	ctx context.Context, key string, query Countable,
+) (int, bool, error) {
+	return f(ctx, key, query)
+}
+
+// ExactCounter counts items by running the `COUNT` query. It is the default
+// counter of Paginator.
+type ExactCounter struct{}
+
+// Count runs the `COUNT` query.
+func (ExactCounter) Count(
+	ctx context.Context, _ string, query Countable,
+) (int, bool, error) {
+	count, err := query.Count(ctx)
+	return count, true, err
+}
+
+// RowQuerier is an interface that defines the method to query a single row.
+// It is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type RowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// PgEstimateCounter estimates the total number of rows of a PostgreSQL table
+// from the `pg_class.reltuples` statistics. As the estimate ignores any
+// predicate of the query, it is only suitable for unfiltered lists.
+type PgEstimateCounter struct {
+	// DB is the database connection to query the statistics.
+	DB RowQuerier
+	// Table is the name of the table, optionally qualified by schema.
+	Table string
+	// Threshold is the number of rows below which the exact count is used,
+	// as estimates of small tables are less reliable.
+	Threshold int
+}
+
+// Count returns the estimated number of rows, or the exact count if the
+// estimate is below `Threshold` or not available.
+func (c PgEstimateCounter) Count(
+	ctx context.Context, key string, query Countable,
+) (int, bool, error) {
+	var estimate float64
+	err := c.DB.QueryRowContext(
+		ctx, "SELECT reltuples FROM pg_class WHERE oid = $1::regclass",
+		c.Table,
+	).Scan(&estimate)
+	if err != nil {
+		return 0, false, errors.Errorf(
+			"failed to estimate rows of %s: %v", c.Table, err,
+		)
+	}
+	// `reltuples` is -1 if the table has never been analyzed.
+	if estimate < 0 || int(estimate) < c.Threshold {
+		return ExactCounter{}.Count(ctx, key, query)
+	}
+	return int(math.Round(estimate)), false, nil
+}
+
+// CachedCounter caches counts of another counter per query key for a period
+// of time. Cached counts are reported as inexact.
+type CachedCounter struct {
+	// Counter is the counter to be cached. Defaults to ExactCounter.
+	Counter Counter
+	// TTL is how long a count is cached.
+	TTL time.Duration
+	// Fingerprint returns the cache key of the query. It defaults to the
+	// query key, i.e. the request URL without pagination parameters. Please
+	// include anything scoping the query but not in the URL, e.g. tenant.
+	Fingerprint func(ctx context.Context, key string) string
+
+	mu      sync.Mutex
+	entries map[string]cachedCount
+	sweepAt int
+}
+
+type cachedCount struct {
+	count   int
+	expires time.Time
+}
+
+// NewCachedCounter returns a CachedCounter of the given counter.
+func NewCachedCounter(counter Counter, ttl time.Duration) *CachedCounter {
+	return &CachedCounter{Counter: counter, TTL: ttl}
+}
+
+// Count returns the cached count, or counts using `Counter` if there is none
+// or it has expired.
+func (c *CachedCounter) Count(
+	ctx context.Context, key string, query Countable,
+) (int, bool, error) {
+	if nil != c.Fingerprint {
+		key = c.Fingerprint(ctx, key)
+	}
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.count, false, nil
+	}
+	counter := c.Counter
+	if nil == counter {
+		counter = ExactCounter{}
+	}
+	count, exact, err := counter.Count(ctx, key, query)
+	if err != nil {
+		return 0, false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nil == c.entries {
+		c.entries = make(map[string]cachedCount)
+	}
+	c.entries[key] = cachedCount{count: count, expires: now.Add(c.TTL)}
+	c.sweep(now)
+	return count, exact, nil
+}
+
+// sweep removes expired entries once the cache doubles its size since the
+// last sweep. Must be called with the lock held.
+func (c *CachedCounter) sweep(now time.Time) {
+	if len(c.entries) < c.sweepAt {
+		return
+	}
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	c.sweepAt = max(2*len(c.entries), 64)
+}
+
+// count counts the given query using `Counter`, or ExactCounter if it is nil.
+// The query key is empty if the paginator has no request, e.g. in GraphQL
+// resolvers.
+func (p Paginator[V, Q]) count(
+	ctx context.Context, query Countable,
+) (int, bool, error) {
+	if nil == p.Counter {
+		return ExactCounter{}.Count(ctx, "", query)
+	}
+	if nil == p.PageRequest && nil == p.GinCtx {
+		return p.Counter.Count(ctx, "", query)
+	}
+	return p.Counter.Count(ctx, p.UrlWithoutPageParams().String(), query)
+}
@@ -0,0 +1,377 @@
+package paginate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/eidng8/go-utils"
+	"github.com/go-faster/errors"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ogen-go/ogen"
+)
+
+// ParamCursor is the query parameter name for the keyset pagination cursor.
+const ParamCursor = "cursor"
+
+// ErrInvalidCursor is returned when the cursor can't be decoded or doesn't
+// match the declared order fields.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SQ is an interface that defines the storage-level methods needed by keyset
+// pagination. The `intercept.Query` returned by the generated
+// `intercept.NewQuery()` function satisfies this interface.
+type SQ interface {
+	Order(...func(*sql.Selector))
+	WhereP(...func(*sql.Selector))
+}
+
+// OrderField declares a column to be used in keyset pagination. The combined
+// order fields must uniquely identify a row, so the last field is usually the
+// primary key. Columns must not be nullable.
+type OrderField[V any] struct {
+	// Column is the database column name.
+	Column string
+	// Desc sorts the column in descending order.
+	Desc bool
+	// Value returns the column value of the given item, e.g. the generated
+	// `PluckXxxYyy()` functions.
+	Value func(*V) any
+}
+
+// CursorList is a struct that contains the keyset paginated list of items.
+type CursorList[T any] struct {
+	// PerPage is the number of items per page.
+	PerPage int `json:"per_page" bson:"per_page" xml:"per_page" yaml:"per_page"`
+	// HasMore tells whether there are more items after the current page.
+	HasMore bool `json:"has_more" bson:"has_more" xml:"has_more" yaml:"has_more"`
+	// NextCursor is the cursor of the next page. It is an empty string if the
+	// current page is the last page.
+	NextCursor string `json:"next_cursor" bson:"next_cursor" xml:"next_cursor" yaml:"next_cursor"`
+	// PrevCursor is the cursor of the previous page. It is an empty string if
+	// the current page is the first page.
+	PrevCursor string `json:"prev_cursor" bson:"prev_cursor" xml:"prev_cursor" yaml:"prev_cursor"`
+	// NextPageUrl is the URL of the next page. It is an empty string if the
+	// current page is the last page.
+	NextPageUrl string `json:"next_page_url" bson:"next_page_url" xml:"next_page_url" yaml:"next_page_url"`
+	// PrevPageUrl is the URL of the previous page. It is an empty string if
+	// the current page is the first page.
+	PrevPageUrl string `json:"prev_page_url" bson:"prev_page_url" xml:"prev_page_url" yaml:"prev_page_url"`
+	// Path is the fully qualified URL without query string.
+	Path string `json:"path" bson:"path" xml:"path" yaml:"path"`
+	// Data is the list of items.
+	Data []*T `json:"data" bson:"data" xml:"data" yaml:"data"`
+}
+
+// cursor is the decoded form of the opaque cursor string.
+type cursor struct {
+	// Values are the order field values of the boundary row.
+	Values []jsoniter.RawMessage `json:"v"`
+	// Before is true if the cursor points to rows before the boundary row.
+	Before bool `json:"b,omitempty"`
+}
+
+// GetCursorPage returns a keyset paginated list of items. Rows are ordered by
+// `OrderFields`, which are applied through `StorageQuery`. So there is no need
+// to add the `ORDER` clause to the query beforehand.
+//
+// It fetches `per_page+1` rows to determine whether there are more rows after
+// the current page, and doesn't count the total number of rows.
+func (p Paginator[V, Q]) GetCursorPage() (*CursorList[V], error) {
+	if nil == p.StorageQuery || len(p.OrderFields) == 0 {
+		return nil, errors.New(
+			"keyset pagination requires StorageQuery and OrderFields",
+		)
+	}
+	params, err := p.paginationParams()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.checkUrlBuilder(); err != nil {
+		return nil, err
+	}
+	if err = p.applyFields(); err != nil {
+		return nil, err
+	}
+	if err = p.applyIncludes(); err != nil {
+		return nil, err
+	}
+	perPage := params.GetPerPage()
+	values, before, err := decodeCursor(params.Cursor, p.OrderFields)
+	if err != nil {
+		return nil, err
+	}
+	p.StorageQuery.Order(orderTerms(p.OrderFields, before)...)
+	if values != nil {
+		p.StorageQuery.WhereP(keysetPredicate(p.OrderFields, values, before))
+	}
+	p.Query.Limit(perPage + 1)
+	rows, err := p.Query.All(p.QueryCtx)
+	if err != nil {
+		return nil, err
+	}
+	more := len(rows) > perPage
+	if more {
+		rows = rows[:perPage]
+	}
+	if before {
+		slices.Reverse(rows)
+	}
+	list := &CursorList[V]{
+		PerPage: perPage,
+		Path:    p.UrlWithoutQuery().String(),
+		Data:    rows,
+	}
+	if len(rows) == 0 {
+		return list, nil
+	}
+	// Moving backward always leaves rows after the current page, and moving
+	// forward from a cursor always leaves rows before it.
+	if more || before {
+		list.NextCursor, err = encodeCursor(
+			p.OrderFields, rows[len(rows)-1], false,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list.NextPageUrl = p.UrlWithCursor(list.NextCursor, perPage).String()
+	}
+	if (more && before) || (!before && values != nil) {
+		list.PrevCursor, err = encodeCursor(p.OrderFields, rows[0], true)
+		if err != nil {
+			return nil, err
+		}
+		list.PrevPageUrl = p.UrlWithCursor(list.PrevCursor, perPage).String()
+	}
+	list.HasMore = "" != list.NextCursor
+	return list, nil
+}
+
+// UrlWithCursor returns a URL with the cursor and per_page query parameters
+// set, and the page parameter removed.
+func (p Paginator[V, Q]) UrlWithCursor(cursor string, perPage int) *url.URL {
+	u := utils.UrlWithQueryParams(
+		*p.requestUrl(), CursorQueryParams(cursor, perPage),
+	)
+	return p.buildUrl(utils.UrlWithoutQueryParams(*u, ParamPage))
+}
+
+// CursorQueryParams sets the cursor and per_page query parameters.
+func CursorQueryParams(cursor string, perPage int) map[string]string {
+	params := make(map[string]string, 2)
+	params[ParamCursor] = cursor
+	params[ParamPerPage] = fmt.Sprintf("%d", perPage)
+	return params
+}
+
+// orderTerms returns the ORDER BY terms of the given fields. The direction of
+// every field is reversed if `reverse` is true.
+func orderTerms[V any](
+	fields []OrderField[V], reverse bool,
+) []func(*sql.Selector) {
+	terms := make([]func(*sql.Selector), len(fields))
+	for i, field := range fields {
+		if field.Desc != reverse {
+			terms[i] = sql.OrderByField(field.Column, sql.OrderDesc()).ToFunc()
+		} else {
+			terms[i] = sql.OrderByField(field.Column).ToFunc()
+		}
+	}
+	return terms
+}
+
+// keysetPredicate returns the predicate that selects rows after (or before,
+// if `before` is true) the row with the given order field values, i.e.
+// `(a > ?) OR (a = ? AND b > ?) OR ...`.
+func keysetPredicate[V any](
+	fields []OrderField[V], values []any, before bool,
+) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		ors := make([]*sql.Predicate, len(fields))
+		for i, field := range fields {
+			ands := make([]*sql.Predicate, 0, i+1)
+			for j := 0; j < i; j++ {
+				ands = append(ands, sql.EQ(s.C(fields[j].Column), values[j]))
+			}
+			if field.Desc != before {
+				ands = append(ands, sql.LT(s.C(field.Column), values[i]))
+			} else {
+				ands = append(ands, sql.GT(s.C(field.Column), values[i]))
+			}
+			ors[i] = sql.And(ands...)
+		}
+		s.Where(sql.Or(ors...))
+	}
+}
+
+// encodeCursor returns the opaque cursor string pointing to the given row.
+func encodeCursor[V any](
+	fields []OrderField[V], row *V, before bool,
+) (string, error) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	c := cursor{
+		Values: make([]jsoniter.RawMessage, len(fields)),
+		Before: before,
+	}
+	for i, field := range fields {
+		value, err := json.Marshal(field.Value(row))
+		if err != nil {
+			return "", errors.Errorf("failed to marshal cursor value: %v", err)
+		}
+		c.Values[i] = value
+	}
+	marshal, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.Errorf("failed to marshal cursor: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(marshal), nil
+}
+
+// decodeCursor decodes the opaque cursor string. It returns nil values if the
+// cursor is empty. Each value is decoded to the type returned by the `Value`
+// function of the corresponding order field.
+func decodeCursor[V any](
+	str string, fields []OrderField[V],
+) ([]any, bool, error) {
+	if "" == str {
+		return nil, false, nil
+	}
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	raw, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, false, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+	var c cursor
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return nil, false, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+	if len(c.Values) != len(fields) {
+		return nil, false, ErrInvalidCursor
+	}
+	var zero V
+	values := make([]any, len(fields))
+	for i, field := range fields {
+		typ := reflect.TypeOf(field.Value(&zero))
+		if nil == typ {
+			return nil, false, errors.Wrap(ErrInvalidCursor, field.Column)
+		}
+		value := reflect.New(typ)
+		if err = json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+			return nil, false, errors.Wrap(ErrInvalidCursor, err.Error())
+		}
+		values[i] = value.Elem().Interface()
+	}
+	return values, c.Before, nil
+}
+
+// AttachCursorTo adds the `cursor` parameter and the keyset paginated
+// response to the given OpenAPI operation.
+func AttachCursorTo(op *ogen.Operation, description string, itemRef string) {
+	FixParamNames(op.Parameters)
+	SetParamLimits(op.Parameters, Limits{})
+	op.AddParameters(CursorParam())
+	SetCursorResponse(op, description, itemRef)
+}
+
+// CursorParam returns the `cursor` query parameter.
+func CursorParam() *ogen.Parameter {
+	return &ogen.Parameter{
+		Name:        ParamCursor,
+		In:          "query",
+		Description: "Opaque cursor of the page to be retrieved",
+		Required:    false,
+		Schema:      &ogen.Schema{Type: "string"},
+	}
+}
+
+// SetCursorResponse changes the response of given OpenAPI operation to meet
+// the keyset paginated response.
+func SetCursorResponse(
+	op *ogen.Operation, description string, itemRef string,
+) {
+	op.Responses["200"] = &ogen.Response{
+		Description: description,
+		Content: map[string]ogen.Media{
+			"application/json": {
+				Schema: &ogen.Schema{
+					Type: "object",
+					Properties: []ogen.Property{
+						{
+							Name: "per_page",
+							Schema: &ogen.Schema{
+								Type:        "integer",
+								Description: "Number of items per page",
+								Minimum:     ogen.Num("1"),
+							},
+						},
+						{
+							Name: "has_more",
+							Schema: &ogen.Schema{
+								Type:        "boolean",
+								Description: "Whether there are more items after the current page",
+							},
+						},
+						{
+							Name: "next_cursor",
+							Schema: &ogen.Schema{
+								Type:        "string",
+								Description: "Cursor of the next page",
+							},
+						},
+						{
+							Name: "prev_cursor",
+							Schema: &ogen.Schema{
+								Type:        "string",
+								Description: "Cursor of the previous page",
+							},
+						},
+						{
+							Name: "next_page_url",
+							Schema: &ogen.Schema{
+								Type:        "string",
+								Description: "URL to the next page",
+							},
+						},
+						{
+							Name: "prev_page_url",
+							Schema: &ogen.Schema{
+								Type:        "string",
+								Description: "URL to the previous page",
+							},
+						},
+						{
+							Name: "path",
+							Schema: &ogen.Schema{
+								Type:        "string",
+								Description: "Base path of the request",
+							},
+						},
+						{
+							Name: "data",
+							Schema: &ogen.Schema{
+								Type:        "array",
+								Description: "List of items",
+								Items: &ogen.Items{
+									Item: &ogen.Schema{Ref: itemRef},
+								},
+							},
+						},
+					},
+					Required: []string{
+						"per_page",
+						"has_more",
+						"next_cursor",
+						"prev_cursor",
+						"next_page_url",
+						"prev_page_url",
+						"path",
+						"data",
+					},
+				},
+			},
+		},
+	}
+}
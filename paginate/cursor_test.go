@@ -0,0 +1,131 @@
+package paginate
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+type testRow struct {
+	Name string
+	ID   int
+}
+
+var testOrderFields = []OrderField[testRow]{
+	{Column: "name", Desc: true, Value: func(r *testRow) any { return r.Name }},
+	{Column: "id", Value: func(r *testRow) any { return r.ID }},
+}
+
+func TestKeysetPredicate(t *testing.T) {
+	tests := []struct {
+		name   string
+		before bool
+		want   string
+	}{
+		{
+			"after", false,
+			"SELECT * FROM `t` WHERE `t`.`name` < ? OR " +
+				"(`t`.`name` = ? AND `t`.`id` > ?)",
+		},
+		{
+			"before", true,
+			"SELECT * FROM `t` WHERE `t`.`name` > ? OR " +
+				"(`t`.`name` = ? AND `t`.`id` < ?)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				s := sql.Select("*").From(sql.Table("t"))
+				keysetPredicate(
+					testOrderFields, []any{"b", 2}, tt.before,
+				)(s)
+				query, args := s.Query()
+				if query != tt.want {
+					t.Errorf("got %s, want %s", query, tt.want)
+				}
+				if want := []any{"b", "b", 2}; !reflect.DeepEqual(args, want) {
+					t.Errorf("got args %v, want %v", args, want)
+				}
+			},
+		)
+	}
+}
+
+func TestOrderTerms(t *testing.T) {
+	tests := []struct {
+		name    string
+		reverse bool
+		want    string
+	}{
+		{"forward", false, "SELECT * FROM `t` ORDER BY `t`.`name` DESC, `t`.`id`"},
+		{"reverse", true, "SELECT * FROM `t` ORDER BY `t`.`name`, `t`.`id` DESC"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				s := sql.Select("*").From(sql.Table("t"))
+				for _, term := range orderTerms(testOrderFields, tt.reverse) {
+					term(s)
+				}
+				if query, _ := s.Query(); query != tt.want {
+					t.Errorf("got %s, want %s", query, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, before := range []bool{false, true} {
+		str, err := encodeCursor(
+			testOrderFields, &testRow{Name: "b", ID: 2}, before,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values, gotBefore, err := decodeCursor(str, testOrderFields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []any{"b", 2}; !reflect.DeepEqual(values, want) {
+			t.Errorf("got %#v, want %#v", values, want)
+		}
+		if gotBefore != before {
+			t.Errorf("got before %v, want %v", gotBefore, before)
+		}
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"not json", encode("v")},
+		{"missing values", encode(`{"v":["b"]}`)},
+		{"extra values", encode(`{"v":["b",2,3]}`)},
+		{"mismatched type", encode(`{"v":["b","2"]}`)},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				_, _, err := decodeCursor(tt.cursor, testOrderFields)
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Errorf("want ErrInvalidCursor, got %v", err)
+				}
+			},
+		)
+	}
+	values, before, err := decodeCursor("", testOrderFields)
+	if nil != values || before || nil != err {
+		t.Errorf("got %v %v %v of the empty cursor", values, before, err)
+	}
+}
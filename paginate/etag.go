@@ -0,0 +1,170 @@
+package paginate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-faster/errors"
+	"github.com/ogen-go/ogen"
+)
+
+const (
+	// HeaderETag is the name of the ETag header.
+	HeaderETag = "ETag"
+
+	// HeaderIfNoneMatch is the name of the If-None-Match header.
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// NotModifiedError is returned by `GetPage()` if the page matches the
+// `If-None-Match` header of the request. It should be responded with status
+// `304` and the ETag header.
+type NotModifiedError struct {
+	// ETag is the entity tag of the page.
+	ETag string
+}
+
+func (e *NotModifiedError) Error() string {
+	return "not modified: " + e.ETag
+}
+
+// etag returns the weak ETag of the page, derived from the last update time
+// of items, the total number of items, the page window, the request URL and
+// the `Accept` header. A *NotModifiedError is returned if it matches the
+// `If-None-Match` header. No ETag is returned if edges are included.
+func (p Paginator[V, Q]) etag(count, offset, limit int) (string, error) {
+	paths, err := p.includePaths()
+	if err != nil || len(paths) > 0 {
+		return "", err
+	}
+	var modified string
+	if count > 0 {
+		if modified, err = p.lastModified(); err != nil {
+			return "", err
+		}
+	}
+	var accept string
+	req := p.httpRequest()
+	if nil != req {
+		accept = req.Header.Get("Accept")
+	}
+	hash := sha256.Sum256(
+		[]byte(
+			fmt.Sprintf(
+				"%s|%d|%d|%d|%s|%s", modified, count, offset, limit,
+				p.requestUrl().String(), accept,
+			),
+		),
+	)
+	etag := `W/"` + hex.EncodeToString(hash[:16]) + `"`
+	if nil != req && etagMatch(req.Header.Get(HeaderIfNoneMatch), etag) {
+		return etag, &NotModifiedError{ETag: etag}
+	}
+	return etag, nil
+}
+
+// MaxQuerier is an interface that defines the method to aggregate the maximum
+// value of a column, which is generated by the PaginateExtension.
+type MaxQuerier interface {
+	// MaxOf returns the maximum value of the column among rows of the query,
+	// formatted as a string, or an empty string if there is no row. The
+	// query itself is left untouched.
+	MaxOf(ctx context.Context, column string) (string, error)
+}
+
+// lastModified returns the last update time of items of the query, as given
+// by the `MAX()` aggregate of the `UpdatedAt` column.
+func (p Paginator[V, Q]) lastModified() (string, error) {
+	query, ok := p.Query.(MaxQuerier)
+	if !ok {
+		return "", errors.Errorf(
+			"ETag requires the MaxOf() method of query %T", p.Query,
+		)
+	}
+	return query.MaxOf(p.QueryCtx, p.UpdatedAt)
+}
+
+// etagMatch tells whether the `If-None-Match` header matches the ETag, using
+// the weak comparison.
+func etagMatch(header string, etag string) bool {
+	if "" == header {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if "*" == tag || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetETag sets the ETag header of the paginated list, if it has one.
+func SetETag[T any](header http.Header, list *PaginatedList[T]) {
+	if "" != list.ETag {
+		header.Set(HeaderETag, list.ETag)
+	}
+}
+
+// GinETagResponse responds the result of `GetPage()` to conditional requests.
+// It responds `304` if `err` is a *NotModifiedError, or the paginated list
+// as JSON along with the ETag header if there is no error. Other errors are
+// returned to the caller without responding.
+func GinETagResponse[T any](
+	gc *gin.Context, status int, list *PaginatedList[T], err error,
+) error {
+	var nm *NotModifiedError
+	if errors.As(err, &nm) {
+		gc.Header(HeaderETag, nm.ETag)
+		gc.Status(http.StatusNotModified)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	SetETag(gc.Writer.Header(), list)
+	gc.JSON(status, list)
+	return nil
+}
+
+// AttachWithETag is like `AttachTo()`, but also adds the If-None-Match
+// parameter, the ETag header and the `304` response.
+func AttachWithETag(op *ogen.Operation, description string, itemRef string) {
+	AttachTo(op, description, itemRef)
+	SetETagResponse(op)
+}
+
+// SetETagResponse adds the If-None-Match parameter, the ETag header of the
+// `200` response, and the `304` response to the given OpenAPI operation. The
+// `200` response is added if there is none.
+func SetETagResponse(op *ogen.Operation) {
+	op.AddParameters(
+		&ogen.Parameter{
+			Name:        HeaderIfNoneMatch,
+			In:          "header",
+			Description: "ETag of the page previously received",
+			Required:    false,
+			Schema:      &ogen.Schema{Type: "string"},
+		},
+	)
+	header := &ogen.Header{
+		Description: "Weak entity tag of the page",
+		Required:    true,
+		Schema:      &ogen.Schema{Type: "string"},
+	}
+	res := okResponse(op)
+	if nil == res.Headers {
+		res.Headers = make(map[string]*ogen.Header, 1)
+	}
+	res.Headers[HeaderETag] = header
+	op.Responses["304"] = &ogen.Response{
+		Description: "The page is not modified",
+		Headers:     map[string]*ogen.Header{HeaderETag: header},
+	}
+}
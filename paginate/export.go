@@ -0,0 +1,279 @@
+package paginate
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"slices"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/go-faster/errors"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// DefaultExportBatchSize is the default number of rows fetched per batch by
+// `Export()`.
+const DefaultExportBatchSize = 500
+
+// ExportFormat is the output format of `Export()`.
+type ExportFormat string
+
+const (
+	// FormatNdjson writes one JSON object per line.
+	FormatNdjson ExportFormat = "ndjson"
+	// FormatCsv writes comma separated values with a header row.
+	FormatCsv ExportFormat = "csv"
+)
+
+// ContentType returns the media type of the format.
+func (f ExportFormat) ContentType() string {
+	if FormatCsv == f {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// ExportEncoder is an interface that defines the methods to write exported
+// rows.
+type ExportEncoder[V any] interface {
+	// Encode writes a batch of rows.
+	Encode(rows []*V) error
+	// Flush sends buffered data to the underlying writer. It is called after
+	// each batch.
+	Flush() error
+}
+
+// NewExportEncoder returns the encoder of the given format. `columns` is only
+// used by FormatCsv.
+func NewExportEncoder[V any](
+	format ExportFormat, w io.Writer, columns []CsvColumn[V],
+) (ExportEncoder[V], error) {
+	switch format {
+	case FormatNdjson:
+		return NewNdjsonEncoder[V](w), nil
+	case FormatCsv:
+		return NewCsvEncoder(w, columns), nil
+	}
+	return nil, errors.Errorf("unknown export format %q", format)
+}
+
+// NdjsonEncoder writes rows as newline delimited JSON.
+type NdjsonEncoder[V any] struct {
+	out io.Writer
+	buf *bufio.Writer
+	enc *jsoniter.Encoder
+}
+
+// NewNdjsonEncoder returns the NDJSON encoder writing to w.
+func NewNdjsonEncoder[V any](w io.Writer) *NdjsonEncoder[V] {
+	buf := bufio.NewWriter(w)
+	return &NdjsonEncoder[V]{
+		out: w,
+		buf: buf,
+		enc: jsoniter.ConfigCompatibleWithStandardLibrary.NewEncoder(buf),
+	}
+}
+
+// Encode writes each row as a line of JSON.
+func (e *NdjsonEncoder[V]) Encode(rows []*V) error {
+	for _, row := range rows {
+		if err := e.enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush flushes the buffer, and the underlying writer if it is an
+// http.Flusher.
+func (e *NdjsonEncoder[V]) Flush() error {
+	return flush(e.out, e.buf.Flush)
+}
+
+// CsvColumn declares a column of CSV export.
+type CsvColumn[V any] struct {
+	// Name is the column name in the header row.
+	Name string
+	// Value returns the column value of the given item.
+	Value func(*V) string
+}
+
+// PickCsvColumns returns the columns of the given names, in the given order,
+// e.g. from a `columns` query parameter. Empty names are ignored, and all
+// columns are returned if no name is given. A *ValidationError is returned if
+// any name is unknown.
+func PickCsvColumns[V any](
+	columns []CsvColumn[V], names ...string,
+) ([]CsvColumn[V], error) {
+	var verr ValidationError
+	picked := make([]CsvColumn[V], 0, len(names))
+	for _, name := range names {
+		if "" == name {
+			continue
+		}
+		idx := slices.IndexFunc(
+			columns, func(c CsvColumn[V]) bool { return c.Name == name },
+		)
+		if idx < 0 {
+			verr.Add("columns", name, "unknown column")
+			continue
+		}
+		picked = append(picked, columns[idx])
+	}
+	if err := verr.errorOrNil(); err != nil {
+		return nil, err
+	}
+	if len(picked) == 0 {
+		return columns, nil
+	}
+	return picked, nil
+}
+
+// CsvEncoder writes rows as CSV, with a header row of column names.
+type CsvEncoder[V any] struct {
+	out     io.Writer
+	csv     *csv.Writer
+	columns []CsvColumn[V]
+	header  bool
+}
+
+// NewCsvEncoder returns the CSV encoder of the given columns writing to w.
+func NewCsvEncoder[V any](w io.Writer, columns []CsvColumn[V]) *CsvEncoder[V] {
+	return &CsvEncoder[V]{out: w, csv: csv.NewWriter(w), columns: columns}
+}
+
+// Encode writes each row as a CSV record.
+func (e *CsvEncoder[V]) Encode(rows []*V) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	record := make([]string, len(e.columns))
+	for _, row := range rows {
+		for i, column := range e.columns {
+			record[i] = column.Value(row)
+		}
+		if err := e.csv.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes the header row if it hasn't been written, then flushes the
+// buffer, and the underlying writer if it is an http.Flusher.
+func (e *CsvEncoder[V]) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return flush(
+		e.out, func() error {
+			e.csv.Flush()
+			return e.csv.Error()
+		},
+	)
+}
+
+func (e *CsvEncoder[V]) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	names := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		names[i] = column.Name
+	}
+	return e.csv.Write(names)
+}
+
+func flush(w io.Writer, buffered func() error) error {
+	if err := buffered(); err != nil {
+		return err
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// Export walks the whole result set of the query in batches of `batchSize`
+// rows, and writes them to the encoder. Rows are ordered by `OrderFields`
+// and fetched by keyset, so memory use is bounded by the batch size and
+// nothing is counted. It stops as soon as `ctx` is canceled, e.g. when the
+// client disconnects, while queries are run with `QueryCtx`. Each batch is
+// fetched by a clone of `Query`, which must implement `Clone() *Q` as ent
+// query builders do, so interceptors don't pile up predicates on it.
+func (p Paginator[V, Q]) Export(
+	ctx context.Context, enc ExportEncoder[V], batchSize int,
+) error {
+	if nil == p.StorageQuery || len(p.OrderFields) == 0 {
+		return errors.New("export requires StorageQuery and OrderFields")
+	}
+	if batchSize < 1 {
+		batchSize = DefaultExportBatchSize
+	}
+	qc, cancel := context.WithCancel(p.queryContext())
+	defer cancel()
+	if nil != ctx {
+		stop := context.AfterFunc(ctx, cancel)
+		defer stop()
+	}
+	// The predicate is evaluated each time the query is executed, so it
+	// follows the last row of the previous batch.
+	var values []any
+	p.StorageQuery.Order(orderTerms(p.OrderFields, false)...)
+	p.StorageQuery.WhereP(
+		func(s *sql.Selector) {
+			if nil != values {
+				keysetPredicate(p.OrderFields, values, false)(s)
+			}
+		},
+	)
+	p.Query.Limit(batchSize)
+	for {
+		if err := qc.Err(); err != nil {
+			return err
+		}
+		query, err := cloneQuery(p.Query)
+		if err != nil {
+			return err
+		}
+		rows, err := query.All(qc)
+		if err != nil {
+			return err
+		}
+		if err = enc.Encode(rows); err != nil {
+			return err
+		}
+		if err = enc.Flush(); err != nil {
+			return err
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+		last := rows[len(rows)-1]
+		values = make([]any, len(p.OrderFields))
+		for i, field := range p.OrderFields {
+			values[i] = field.Value(last)
+		}
+	}
+}
+
+// GinExport streams the whole result set in the given format as the gin
+// response, using chunked transfer encoding. Export is stopped if the client
+// disconnects. As the status is sent before rows are fetched, errors can only
+// be logged by the caller.
+func (p Paginator[V, Q]) GinExport(
+	gc *gin.Context, format ExportFormat, columns []CsvColumn[V],
+	batchSize int,
+) error {
+	enc, err := NewExportEncoder(format, gc.Writer, columns)
+	if err != nil {
+		return err
+	}
+	gc.Header("Content-Type", format.ContentType())
+	gc.Status(http.StatusOK)
+	return p.Export(gc.Request.Context(), enc, batchSize)
+}
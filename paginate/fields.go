@@ -0,0 +1,136 @@
+package paginate
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/go-faster/errors"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ogen-go/ogen"
+)
+
+// ParamFields is the query parameter name for sparse fieldsets, e.g.
+// `fields=id,name` only fetches the `id` and `name` columns.
+const ParamFields = "fields"
+
+// ParseFields parses the `fields` parameter against the given allowed column
+// names, e.g. the generated `Columns` variable. A *ValidationError is returned
+// if any field is not allowed.
+func ParseFields(value string, allowed []string) ([]string, error) {
+	var verr ValidationError
+	var fields []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if "" == name || slices.Contains(fields, name) {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			verr.Add(ParamFields, value, "unknown field "+name)
+			continue
+		}
+		fields = append(fields, name)
+	}
+	if err := verr.errorOrNil(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// SelectColumns limits the columns fetched by the query to the given ones.
+// It works for any storage-level query, e.g. `intercept.NewQuery(query)` of
+// detail endpoints. The `COUNT` query is not affected.
+func SelectColumns(query SQ, columns ...string) {
+	query.WhereP(
+		func(s *sql.Selector) {
+			s.Select(s.Columns(columns...)...)
+		},
+	)
+}
+
+// ApplyFields parses the `fields` parameter from the given query values, and
+// limits the columns fetched by the query accordingly. `idColumn` and `extra`
+// columns are always fetched. It does nothing if the parameter is absent.
+func ApplyFields(
+	query SQ, values url.Values, allowed []string, idColumn string,
+	extra ...string,
+) error {
+	value := strings.Join(values[ParamFields], ",")
+	if "" == value {
+		return nil
+	}
+	fields, err := ParseFields(value, allowed)
+	if err != nil {
+		return err
+	}
+	columns := []string{idColumn}
+	for _, column := range append(fields, extra...) {
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	SelectColumns(query, columns...)
+	return nil
+}
+
+// applyFields limits the columns fetched by the query to the `fields`
+// parameter of the request, along with the columns needed by the paginator
+// and included edges.
+// It does nothing if no field is allowed or the parameter is absent.
+func (p Paginator[V, Q]) applyFields() error {
+	if len(p.Fields) == 0 || "" == p.requestUrl().Query().Get(ParamFields) {
+		return nil
+	}
+	if nil == p.StorageQuery {
+		return errors.New("sparse fieldsets require StorageQuery")
+	}
+	idColumn := p.IdColumn
+	if "" == idColumn {
+		idColumn = "id"
+	}
+	var extra []string
+	for _, field := range p.OrderFields {
+		extra = append(extra, field.Column)
+	}
+	paths, err := p.includePaths()
+	if err != nil {
+		return err
+	}
+	extra = append(extra, IncludeColumns(paths)...)
+	return ApplyFields(
+		p.StorageQuery, p.requestUrl().Query(), p.Fields, idColumn, extra...,
+	)
+}
+
+// AddFieldsParam adds the `fields` parameter, with the given allowed field
+// names, to the OpenAPI operation.
+func AddFieldsParam(op *ogen.Operation, fields ...string) {
+	op.AddParameters(FieldsParam(fields...))
+}
+
+// FieldsParam returns the `fields` query parameter, which is a comma
+// separated list of the given field names.
+func FieldsParam(fields ...string) *ogen.Parameter {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	enum := make(ogen.Enum, 0, len(fields))
+	for _, field := range fields {
+		name, _ := json.Marshal(field)
+		enum = append(enum, name)
+	}
+	explode := false
+	return &ogen.Parameter{
+		Name:        ParamFields,
+		In:          "query",
+		Description: "Comma separated fields to be returned, `id` is always returned",
+		Required:    false,
+		Style:       "form",
+		Explode:     &explode,
+		Schema: &ogen.Schema{
+			Type: "array",
+			Items: &ogen.Items{
+				Item: &ogen.Schema{Type: "string", Enum: enum},
+			},
+		},
+	}
+}
@@ -0,0 +1,563 @@
+package paginate
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-faster/errors"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ogen-go/ogen"
+)
+
+const (
+	// MediaJson is the media type of the default paginated list.
+	MediaJson = "application/json"
+
+	// MediaJsonApi is the media type of JSON:API documents.
+	MediaJsonApi = "application/vnd.api+json"
+
+	// MediaHal is the media type of HAL documents.
+	MediaHal = "application/hal+json"
+)
+
+// JsonApiDocument is the JSON:API representation of a paginated list.
+type JsonApiDocument[T any] struct {
+	// Meta holds the pagination information.
+	Meta JsonApiMeta `json:"meta" bson:"meta" xml:"meta" yaml:"meta"`
+	// Links holds the pagination links.
+	Links JsonApiLinks `json:"links" bson:"links" xml:"links" yaml:"links"`
+	// Data is the list of resource objects.
+	Data []JsonApiResource[T] `json:"data" bson:"data" xml:"data" yaml:"data"`
+}
+
+// JsonApiMeta is the `meta` member of a paginated JSON:API document.
+type JsonApiMeta struct {
+	// Total is the total number of items.
+	Total int `json:"total" bson:"total" xml:"total" yaml:"total"`
+	// TotalExact tells whether Total is exact, or an estimate of `Counter`.
+	TotalExact bool `json:"total_exact" bson:"total_exact" xml:"total_exact" yaml:"total_exact"`
+	// PerPage is the number of items per page.
+	PerPage int `json:"per_page" bson:"per_page" xml:"per_page" yaml:"per_page"`
+	// CurrentPage is the current page number.
+	CurrentPage int `json:"current_page" bson:"current_page" xml:"current_page" yaml:"current_page"`
+	// LastPage is the last page number.
+	LastPage int `json:"last_page" bson:"last_page" xml:"last_page" yaml:"last_page"`
+	// From is the starting 1-based index of the items.
+	From int `json:"from" bson:"from" xml:"from" yaml:"from"`
+	// To is the ending 1-based index of the items.
+	To int `json:"to" bson:"to" xml:"to" yaml:"to"`
+}
+
+// JsonApiLinks is the `links` member of a paginated JSON:API document.
+// Absent links are `null` as required by the specification.
+type JsonApiLinks struct {
+	// Self is the URL of the current page.
+	Self string `json:"self" bson:"self" xml:"self" yaml:"self"`
+	// First is the URL of the first page.
+	First *string `json:"first" bson:"first" xml:"first" yaml:"first"`
+	// Last is the URL of the last page.
+	Last *string `json:"last" bson:"last" xml:"last" yaml:"last"`
+	// Prev is the URL of the previous page.
+	Prev *string `json:"prev" bson:"prev" xml:"prev" yaml:"prev"`
+	// Next is the URL of the next page.
+	Next *string `json:"next" bson:"next" xml:"next" yaml:"next"`
+}
+
+// JsonApiResource is a JSON:API resource object.
+type JsonApiResource[T any] struct {
+	// Type is the resource type.
+	Type string `json:"type" bson:"type" xml:"type" yaml:"type"`
+	// Id is the resource identifier. It defaults to the `id` member of
+	// Attributes when encoded to JSON.
+	Id string `json:"id" bson:"id" xml:"id" yaml:"id"`
+	// Attributes is the item itself. Its `id` and `type` members are omitted
+	// when encoded to JSON, as they are reserved by JSON:API. Its `edges`
+	// member, i.e. eager-loaded edges of ent, is moved to the `meta` member
+	// of the resource.
+	Attributes *T `json:"attributes" bson:"attributes" xml:"attributes" yaml:"attributes"`
+}
+
+// MarshalJSON implements json.Marshaler. It omits the `id` and `type`
+// members of Attributes, and moves its non-empty `edges` member to `meta`.
+// An error is returned if the resource has no identifier.
+func (r JsonApiResource[T]) MarshalJSON() ([]byte, error) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	raw, err := json.Marshal(r.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	var attributes map[string]jsoniter.RawMessage
+	if err = json.Unmarshal(raw, &attributes); err != nil {
+		return nil, err
+	}
+	id := r.Id
+	if "" == id {
+		id = rawId(attributes["id"])
+	}
+	if "" == id {
+		return nil, errors.Errorf("JSON:API resource %q has no id", r.Type)
+	}
+	var meta map[string]jsoniter.RawMessage
+	if edges, ok := attributes["edges"]; ok {
+		var loaded map[string]jsoniter.RawMessage
+		if nil == json.Unmarshal(edges, &loaded) && len(loaded) > 0 {
+			meta = map[string]jsoniter.RawMessage{"edges": edges}
+		}
+	}
+	delete(attributes, "id")
+	delete(attributes, "type")
+	delete(attributes, "edges")
+	return json.Marshal(
+		struct {
+			Type       string                         `json:"type"`
+			Id         string                         `json:"id"`
+			Attributes map[string]jsoniter.RawMessage `json:"attributes"`
+			Meta       map[string]jsoniter.RawMessage `json:"meta,omitempty"`
+		}{r.Type, id, attributes, meta},
+	)
+}
+
+// rawId returns the identifier of the raw JSON value, which is either a
+// string or a number. It returns an empty string for other values.
+func rawId(raw jsoniter.RawMessage) string {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	var id any
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return ""
+	}
+	switch v := id.(type) {
+	case string:
+		return v
+	case float64:
+		return string(raw)
+	}
+	return ""
+}
+
+// HalDocument is the HAL representation of a paginated list.
+type HalDocument[T any] struct {
+	// Links holds the pagination links.
+	Links map[string]HalLink `json:"_links" bson:"_links" xml:"_links" yaml:"_links"`
+	// Embedded holds the list of items under the relation name.
+	Embedded map[string][]*T `json:"_embedded" bson:"_embedded" xml:"_embedded" yaml:"_embedded"`
+	// Total is the total number of items.
+	Total int `json:"total" bson:"total" xml:"total" yaml:"total"`
+	// TotalExact tells whether Total is exact, or an estimate of `Counter`.
+	TotalExact bool `json:"total_exact" bson:"total_exact" xml:"total_exact" yaml:"total_exact"`
+	// PerPage is the number of items per page.
+	PerPage int `json:"per_page" bson:"per_page" xml:"per_page" yaml:"per_page"`
+	// CurrentPage is the current page number.
+	CurrentPage int `json:"current_page" bson:"current_page" xml:"current_page" yaml:"current_page"`
+	// LastPage is the last page number.
+	LastPage int `json:"last_page" bson:"last_page" xml:"last_page" yaml:"last_page"`
+}
+
+// HalLink is a HAL link object.
+type HalLink struct {
+	// Href is the URL of the link.
+	Href string `json:"href" bson:"href" xml:"href" yaml:"href"`
+}
+
+// Renderer renders a paginated list in the format negotiated by the `Accept`
+// header of the request.
+type Renderer[T any] struct {
+	// Type is the JSON:API resource type of items.
+	Type string
+	// Id returns the JSON:API resource identifier of an item. The `id`
+	// member of the item is used if it is nil.
+	Id func(*T) string
+	// Rel is the HAL relation name of embedded items. Defaults to `items`.
+	Rel string
+	// Formats is the list of media types to be offered, in order of
+	// preference. Defaults to MediaJson, MediaJsonApi and MediaHal.
+	Formats []string
+}
+
+// JsonApi returns the JSON:API document of the paginated list.
+func (r Renderer[T]) JsonApi(list *PaginatedList[T]) *JsonApiDocument[T] {
+	data := make([]JsonApiResource[T], len(list.Data))
+	for i, item := range list.Data {
+		data[i] = JsonApiResource[T]{Type: r.Type, Attributes: item}
+		if nil != r.Id {
+			data[i].Id = r.Id(item)
+		}
+	}
+	return &JsonApiDocument[T]{
+		Meta: JsonApiMeta{
+			Total:       list.Total,
+			TotalExact:  list.TotalExact,
+			PerPage:     list.PerPage,
+			CurrentPage: list.CurrentPage,
+			LastPage:    list.LastPage,
+			From:        list.From,
+			To:          list.To,
+		},
+		Links: JsonApiLinks{
+			Self:  selfUrl(list),
+			First: optionalUrl(list.FirstPageUrl),
+			Last:  optionalUrl(list.LastPageUrl),
+			Prev:  optionalUrl(list.PrevPageUrl),
+			Next:  optionalUrl(list.NextPageUrl),
+		},
+		Data: data,
+	}
+}
+
+// Hal returns the HAL document of the paginated list.
+func (r Renderer[T]) Hal(list *PaginatedList[T]) *HalDocument[T] {
+	rel := r.Rel
+	if "" == rel {
+		rel = "items"
+	}
+	links := map[string]HalLink{"self": {Href: selfUrl(list)}}
+	rels := [][2]string{
+		{"first", list.FirstPageUrl},
+		{"last", list.LastPageUrl},
+		{"next", list.NextPageUrl},
+		{"prev", list.PrevPageUrl},
+	}
+	for _, link := range rels {
+		if "" != link[1] {
+			links[link[0]] = HalLink{Href: link[1]}
+		}
+	}
+	return &HalDocument[T]{
+		Links:       links,
+		Embedded:    map[string][]*T{rel: list.Data},
+		Total:       list.Total,
+		TotalExact:  list.TotalExact,
+		PerPage:     list.PerPage,
+		CurrentPage: list.CurrentPage,
+		LastPage:    list.LastPage,
+	}
+}
+
+// Render returns the media type negotiated by the `Accept` header, and the
+// paginated list in that format.
+func (r Renderer[T]) Render(accept string, list *PaginatedList[T]) (
+	string, any,
+) {
+	switch media := Negotiate(accept, r.formats()...); media {
+	case MediaJsonApi:
+		return media, r.JsonApi(list)
+	case MediaHal:
+		return media, r.Hal(list)
+	default:
+		return MediaJson, list
+	}
+}
+
+// Write writes the paginated list to the response in the format negotiated
+// by the `Accept` header of the request.
+func (r Renderer[T]) Write(
+	w http.ResponseWriter, req *http.Request, status int,
+	list *PaginatedList[T],
+) error {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	media, body := r.Render(req.Header.Get("Accept"), list)
+	w.Header().Set("Content-Type", contentType(media))
+	addVary(w.Header())
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(body)
+}
+
+// Gin is the gin adapter of `Write()`.
+func (r Renderer[T]) Gin(gc *gin.Context, status int, list *PaginatedList[T]) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	media, body := r.Render(gc.GetHeader("Accept"), list)
+	bytes, err := json.Marshal(body)
+	if err != nil {
+		_ = gc.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	addVary(gc.Writer.Header())
+	gc.Data(status, contentType(media), bytes)
+}
+
+// addVary adds `Accept` to the Vary header, keeping values set by others,
+// e.g. CORS middlewares.
+func addVary(header http.Header) {
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(name), "Accept") {
+				return
+			}
+		}
+	}
+	header.Add("Vary", "Accept")
+}
+
+// contentType returns the Content-Type header of the media type. JSON:API
+// forbids media type parameters other than `ext` and `profile`, so `charset`
+// is only added to other media types.
+func contentType(media string) string {
+	if MediaJsonApi == media {
+		return media
+	}
+	return media + "; charset=utf-8"
+}
+
+func (r Renderer[T]) formats() []string {
+	if len(r.Formats) > 0 {
+		return r.Formats
+	}
+	return []string{MediaJson, MediaJsonApi, MediaHal}
+}
+
+// Negotiate returns the offered media type that best matches the `Accept`
+// header, honoring quality values. The first offer is returned if the header
+// is empty or nothing matches.
+func Negotiate(accept string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	best, bestQ, bestSpecificity := offers[0], -1.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		media, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		for _, offer := range offers {
+			specificity := mediaMatch(media, offer)
+			if specificity < 0 {
+				continue
+			}
+			if q > bestQ || (q == bestQ && specificity > bestSpecificity) {
+				best, bestQ, bestSpecificity = offer, q, specificity
+			}
+			break
+		}
+	}
+	return best
+}
+
+// mediaMatch returns how specific the media range matches the offer, or -1 if
+// it doesn't match.
+func mediaMatch(media string, offer string) int {
+	switch {
+	case media == offer:
+		return 2
+	case "*/*" == media:
+		return 0
+	case strings.HasSuffix(media, "/*") &&
+		strings.HasPrefix(offer, strings.TrimSuffix(media, "*")):
+		return 1
+	}
+	return -1
+}
+
+// selfUrl returns the URL of the current page, derived from the first page
+// URL.
+func selfUrl[T any](list *PaginatedList[T]) string {
+	u, err := url.Parse(list.FirstPageUrl)
+	if err != nil {
+		return list.FirstPageUrl
+	}
+	query := u.Query()
+	query.Set(ParamPage, strconv.Itoa(list.CurrentPage))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func optionalUrl(u string) *string {
+	if "" == u {
+		return nil
+	}
+	return &u
+}
+
+// AttachWithFormats is like `AttachTo()`, but lists JSON:API and HAL media
+// types in the response as well.
+func AttachWithFormats(
+	op *ogen.Operation, description string, itemRef string,
+	formats ...string,
+) {
+	FixParamNames(op.Parameters)
+	SetParamLimits(op.Parameters, Limits{})
+	SetFormatsResponse(op, description, itemRef, formats...)
+}
+
+// SetFormatsResponse changes the response of given OpenAPI operation to list
+// the paginated list in each of the given media types. All of MediaJson,
+// MediaJsonApi and MediaHal are listed if no format is given.
+func SetFormatsResponse(
+	op *ogen.Operation, description string, itemRef string,
+	formats ...string,
+) {
+	if len(formats) == 0 {
+		formats = []string{MediaJson, MediaJsonApi, MediaHal}
+	}
+	SetResponse(op, description, itemRef)
+	res := okResponse(op)
+	if nil == res.Content {
+		res.Content = make(map[string]ogen.Media, len(formats))
+	}
+	if !slices.Contains(formats, MediaJson) {
+		delete(res.Content, MediaJson)
+	}
+	if slices.Contains(formats, MediaJsonApi) {
+		res.Content[MediaJsonApi] = ogen.Media{
+			Schema: jsonApiSchema(itemRef),
+		}
+	}
+	if slices.Contains(formats, MediaHal) {
+		res.Content[MediaHal] = ogen.Media{Schema: halSchema(itemRef)}
+	}
+}
+
+func jsonApiSchema(itemRef string) *ogen.Schema {
+	link := &ogen.Schema{Type: "string", Nullable: true}
+	return &ogen.Schema{
+		Type: "object",
+		Properties: []ogen.Property{
+			{
+				Name: "meta",
+				Schema: &ogen.Schema{
+					Type:        "object",
+					Description: "Pagination information",
+					Properties: []ogen.Property{
+						{Name: "total", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("0")}},
+						{Name: "total_exact", Schema: &ogen.Schema{Type: "boolean"}},
+						{Name: "per_page", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("1")}},
+						{Name: "current_page", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("1")}},
+						{Name: "last_page", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("1")}},
+						{Name: "from", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("0")}},
+						{Name: "to", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("0")}},
+					},
+					Required: []string{
+						"total", "total_exact", "per_page", "current_page",
+						"last_page", "from", "to",
+					},
+				},
+			},
+			{
+				Name: "links",
+				Schema: &ogen.Schema{
+					Type:        "object",
+					Description: "Pagination links",
+					Properties: []ogen.Property{
+						{Name: "self", Schema: &ogen.Schema{Type: "string"}},
+						{Name: "first", Schema: link},
+						{Name: "last", Schema: link},
+						{Name: "prev", Schema: link},
+						{Name: "next", Schema: link},
+					},
+					Required: []string{"self", "first", "last", "prev", "next"},
+				},
+			},
+			{
+				Name: "data",
+				Schema: &ogen.Schema{
+					Type:        "array",
+					Description: "List of resource objects",
+					Items: &ogen.Items{
+						Item: &ogen.Schema{
+							Type: "object",
+							Properties: []ogen.Property{
+								{Name: "type", Schema: &ogen.Schema{Type: "string"}},
+								{Name: "id", Schema: &ogen.Schema{Type: "string"}},
+								{
+									Name: "attributes",
+									Schema: &ogen.Schema{
+										Type: "object",
+										Description: "Members of " + itemRef +
+											", except `id`, `type` and `edges`",
+										AdditionalProperties: &ogen.AdditionalProperties{
+											Schema: ogen.Schema{},
+										},
+									},
+								},
+								{
+									Name: "meta",
+									Schema: &ogen.Schema{
+										Type:        "object",
+										Description: "Eager-loaded edges of the item, if any",
+										Properties: []ogen.Property{
+											{
+												Name: "edges",
+												Schema: &ogen.Schema{
+													Type: "object",
+													AdditionalProperties: &ogen.AdditionalProperties{
+														Schema: ogen.Schema{},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							Required: []string{"type", "id", "attributes"},
+						},
+					},
+				},
+			},
+		},
+		Required: []string{"meta", "links", "data"},
+	}
+}
+
+func halSchema(itemRef string) *ogen.Schema {
+	link := &ogen.Schema{
+		Type: "object",
+		Properties: []ogen.Property{
+			{Name: "href", Schema: &ogen.Schema{Type: "string"}},
+		},
+		Required: []string{"href"},
+	}
+	return &ogen.Schema{
+		Type: "object",
+		Properties: []ogen.Property{
+			{
+				Name: "_links",
+				Schema: &ogen.Schema{
+					Type:        "object",
+					Description: "Pagination links",
+					Properties: []ogen.Property{
+						{Name: "self", Schema: link},
+						{Name: "first", Schema: link},
+						{Name: "last", Schema: link},
+						{Name: "prev", Schema: link},
+						{Name: "next", Schema: link},
+					},
+					Required: []string{"self"},
+				},
+			},
+			{
+				Name: "_embedded",
+				Schema: &ogen.Schema{
+					Type:        "object",
+					Description: "List of items under the relation name",
+					AdditionalProperties: &ogen.AdditionalProperties{
+						Schema: ogen.Schema{
+							Type:  "array",
+							Items: &ogen.Items{Item: &ogen.Schema{Ref: itemRef}},
+						},
+					},
+				},
+			},
+			{Name: "total", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("0")}},
+			{Name: "total_exact", Schema: &ogen.Schema{Type: "boolean"}},
+			{Name: "per_page", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("1")}},
+			{Name: "current_page", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("1")}},
+			{Name: "last_page", Schema: &ogen.Schema{Type: "integer", Minimum: ogen.Num("1")}},
+		},
+		Required: []string{
+			"_links", "_embedded", "total", "total_exact", "per_page",
+			"current_page", "last_page",
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package paginate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+type testItem struct {
+	ID    int           `json:"id"`
+	Type  string        `json:"type"`
+	Name  string        `json:"name"`
+	Edges testItemEdges `json:"edges"`
+}
+
+type testItemEdges struct {
+	Owner *testItem `json:"owner,omitempty"`
+}
+
+func TestJsonApiResourceMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource JsonApiResource[testItem]
+		want     string
+	}{
+		{
+			"id from attributes",
+			JsonApiResource[testItem]{
+				Type: "items", Attributes: &testItem{ID: 1, Type: "x", Name: "a"},
+			},
+			`{"type":"items","id":"1","attributes":{"name":"a"}}`,
+		},
+		{
+			"explicit id",
+			JsonApiResource[testItem]{
+				Type: "items", Id: "a", Attributes: &testItem{ID: 1, Name: "a"},
+			},
+			`{"type":"items","id":"a","attributes":{"name":"a"}}`,
+		},
+		{
+			"edges in meta",
+			JsonApiResource[testItem]{
+				Type: "items",
+				Attributes: &testItem{
+					ID: 1, Name: "a",
+					Edges: testItemEdges{Owner: &testItem{ID: 2, Name: "b"}},
+				},
+			},
+			`{"type":"items","id":"1","attributes":{"name":"a"},` +
+				`"meta":{"edges":{"owner":{"id":2,"type":"","name":"b","edges":{}}}}}`,
+		},
+	}
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := json.Marshal(tt.resource)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != tt.want {
+					t.Errorf("got %s, want %s", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestJsonApiResourceWithoutId(t *testing.T) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	_, err := json.Marshal(
+		JsonApiResource[testItemEdges]{
+			Type: "items", Attributes: &testItemEdges{},
+		},
+	)
+	if nil == err {
+		t.Error("want error of the missing id")
+	}
+}
+
+func TestRendererVary(t *testing.T) {
+	tests := []struct {
+		name string
+		vary []string
+		want []string
+	}{
+		{"absent", nil, []string{"Accept"}},
+		{"kept", []string{"Origin"}, []string{"Origin", "Accept"}},
+		{"present", []string{"Origin, accept"}, []string{"Origin, accept"}},
+	}
+	list := &PaginatedList[testItem]{Data: []*testItem{{ID: 1}}}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/items", nil)
+				req.Header.Set("Accept", MediaJsonApi)
+				w := httptest.NewRecorder()
+				for _, value := range tt.vary {
+					w.Header().Add("Vary", value)
+				}
+				err := Renderer[testItem]{Type: "items"}.Write(w, req, 200, list)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := w.Header().Values("Vary"); !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{MediaJson, MediaJsonApi, MediaHal}
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"empty", "", MediaJson},
+		{"exact", MediaHal, MediaHal},
+		{"any", "*/*", MediaJson},
+		{"unknown", "text/html", MediaJson},
+		{"highest q wins", MediaJsonApi + ";q=0.5, " + MediaHal + ";q=0.9", MediaHal},
+		{"default q is 1", MediaJsonApi + ", " + MediaHal + ";q=0.9", MediaJsonApi},
+		{"exact precedes range of same q", "application/*, " + MediaHal, MediaHal},
+		{"exact precedes any of same q", "*/*;q=0.8, " + MediaJsonApi + ";q=0.8", MediaJsonApi},
+		{"range", "application/*;q=0.5, text/html", MediaJson},
+		{"zero q excluded", MediaJson + ";q=0, " + MediaHal + ";q=0.1", MediaHal},
+		{"invalid q skipped", MediaHal + ";q=x, " + MediaJsonApi + ";q=0.1", MediaJsonApi},
+		{"case-insensitive media", "Application/HAL+JSON", MediaHal},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := Negotiate(tt.accept, offers...); got != tt.want {
+					t.Errorf("got %s, want %s", got, tt.want)
+				}
+			},
+		)
+	}
+	if got := Negotiate(MediaHal); "" != got {
+		t.Errorf("got %s without offers", got)
+	}
+}
@@ -0,0 +1,40 @@
+package paginate
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// GetPaginationParams returns the PaginatedParams from the gin.Context,
+// with default values of page `1` and `10` items per page.
+func GetPaginationParams(gc *gin.Context) PaginatedParams {
+	return GetPaginationParamsWithDefault(gc, 1, 10)
+}
+
+// GetPaginationParamsWithDefault returns the PaginatedParams from the
+// gin.Context with given default values.
+func GetPaginationParamsWithDefault(
+	gc *gin.Context, defaultPage, defaultPerPage int,
+) PaginatedParams {
+	var page PaginatedParams
+	if gc.ShouldBind(&page) != nil {
+		page.Page = defaultPage
+		page.PerPage = defaultPerPage
+	}
+	if page.Page < 1 {
+		page.Page = defaultPage
+	}
+	if page.PerPage < 1 {
+		page.PerPage = defaultPerPage
+	}
+	return page
+}
+
+// NewGinPageRequest returns the PageRequest of the given gin.Context, with
+// default values of page `1` and `10` items per page.
+func NewGinPageRequest(gc *gin.Context) *PageRequest {
+	return &PageRequest{
+		Url:     gc.Request.URL,
+		Request: gc.Request,
+		Params:  GetPaginationParams(gc),
+	}
+}
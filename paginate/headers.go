@@ -0,0 +1,151 @@
+package paginate
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ogen-go/ogen"
+)
+
+const (
+	// HeaderLink is the name of the RFC 8288 Link header.
+	HeaderLink = "Link"
+
+	// HeaderTotalCount is the name of the header holding the total number of
+	// items.
+	HeaderTotalCount = "X-Total-Count"
+)
+
+// LinkHeader returns the RFC 8288 Link header value of the paginated list,
+// with `first`, `last`, `next` and `prev` relations. Relations without URL
+// are omitted.
+func LinkHeader[T any](list *PaginatedList[T]) string {
+	rels := [][2]string{
+		{"first", list.FirstPageUrl},
+		{"last", list.LastPageUrl},
+		{"next", list.NextPageUrl},
+		{"prev", list.PrevPageUrl},
+	}
+	links := make([]string, 0, len(rels))
+	for _, rel := range rels {
+		if "" != rel[1] {
+			links = append(links, fmt.Sprintf("<%s>; rel=\"%s\"", rel[1], rel[0]))
+		}
+	}
+	return strings.Join(links, ", ")
+}
+
+// SetHeaders sets the Link and X-Total-Count headers of the paginated list.
+func SetHeaders[T any](header http.Header, list *PaginatedList[T]) {
+	if link := LinkHeader(list); "" != link {
+		header.Set(HeaderLink, link)
+	}
+	header.Set(HeaderTotalCount, strconv.Itoa(list.Total))
+}
+
+// WriteResponse writes the paginated list as JSON to the response, along with
+// the Link and X-Total-Count headers. If `headersOnly` is true, the body is
+// just the data array.
+func WriteResponse[T any](
+	w http.ResponseWriter, status int, list *PaginatedList[T],
+	headersOnly bool,
+) error {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	SetHeaders(w.Header(), list)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if headersOnly {
+		return json.NewEncoder(w).Encode(list.Data)
+	}
+	return json.NewEncoder(w).Encode(list)
+}
+
+// GinResponse is the gin adapter of `WriteResponse()`.
+func GinResponse[T any](
+	gc *gin.Context, status int, list *PaginatedList[T], headersOnly bool,
+) {
+	SetHeaders(gc.Writer.Header(), list)
+	if headersOnly {
+		gc.JSON(status, list.Data)
+		return
+	}
+	gc.JSON(status, list)
+}
+
+// AttachWithHeaders is like `AttachTo()`, but also adds the Link and
+// X-Total-Count headers to the response. If `headersOnly` is true, the
+// response body is just the data array.
+func AttachWithHeaders(
+	op *ogen.Operation, description string, itemRef string, headersOnly bool,
+) {
+	FixParamNames(op.Parameters)
+	SetParamLimits(op.Parameters, Limits{})
+	if headersOnly {
+		SetHeadersOnlyResponse(op, description, itemRef)
+		return
+	}
+	SetResponse(op, description, itemRef)
+	SetHeadersResponse(op)
+}
+
+// SetHeadersResponse adds the Link and X-Total-Count headers to the `200`
+// response of given OpenAPI operation. The response is added if there is
+// none.
+func SetHeadersResponse(op *ogen.Operation) {
+	res := okResponse(op)
+	if nil == res.Headers {
+		res.Headers = make(map[string]*ogen.Header, 2)
+	}
+	res.Headers[HeaderLink] = &ogen.Header{
+		Description: "RFC 8288 links to the first, last, next and previous pages",
+		Schema:      &ogen.Schema{Type: "string"},
+	}
+	res.Headers[HeaderTotalCount] = &ogen.Header{
+		Description: "Total number of items",
+		Required:    true,
+		Schema: &ogen.Schema{
+			Type:    "integer",
+			Minimum: ogen.Num("0"),
+		},
+	}
+}
+
+// SetHeadersOnlyResponse changes the response of given OpenAPI operation to
+// an array of items, with pagination information in headers.
+func SetHeadersOnlyResponse(
+	op *ogen.Operation, description string, itemRef string,
+) {
+	op.Responses["200"] = &ogen.Response{
+		Description: description,
+		Content: map[string]ogen.Media{
+			"application/json": {
+				Schema: &ogen.Schema{
+					Type:        "array",
+					Description: "List of items",
+					Items: &ogen.Items{
+						Item: &ogen.Schema{Ref: itemRef},
+					},
+				},
+			},
+		},
+	}
+	SetHeadersResponse(op)
+}
+
+// okResponse returns the `200` response of the OpenAPI operation, adding an
+// empty one if there is none.
+func okResponse(op *ogen.Operation) *ogen.Response {
+	if nil == op.Responses {
+		op.Responses = make(ogen.Responses, 1)
+	}
+	res := op.Responses["200"]
+	if nil == res {
+		res = &ogen.Response{Description: http.StatusText(http.StatusOK)}
+		op.Responses["200"] = res
+	}
+	return res
+}
@@ -26,6 +26,68 @@ type Paginator[V any, Q any] struct {
 	Query    PQ[V, Q]
 	GinCtx   *gin.Context
 	QueryCtx context.Context
+	// StorageQuery is the storage-level view of `Query`, required by keyset
+	// pagination, e.g. the result of `intercept.NewQuery(query)`.
+	StorageQuery SQ
+	// OrderFields declares the columns used by keyset pagination.
+	OrderFields []OrderField[V]
+	// Concurrent runs the count and the page fetch in parallel on a clone of
+	// `Query`, which must implement `Clone() *Q` as ent query builders do.
+	// Don't use it with transactional clients, as a transaction can't be used
+	// by multiple statements at the same time.
+	Concurrent bool
+	// Counter is the strategy to count the total number of items. Defaults to
+	// ExactCounter.
+	Counter Counter
+	// PageRequest is the framework-agnostic request information. `GinCtx` is
+	// used if it is nil.
+	PageRequest *PageRequest
+	// Sorts is the whitelist of fields that can be sorted by the `sort`
+	// parameter, which requires `StorageQuery`.
+	Sorts map[string]SortFunc
+	// DefaultOrder orders the query after the `sort` parameter is applied,
+	// so it is the last tie-breaker of the order, e.g. by the primary key.
+	// The generated `Paginate()` method orders by ID if the query is not
+	// ordered by any option.
+	DefaultOrder func()
+	// Limits configures the validation of pagination parameters. If it is
+	// nil, `per_page` is clamped to `DefaultMaxPerPage`.
+	Limits *Limits
+	// UrlBuilder is the strategy to build URLs of pagination links, e.g.
+	// ForwardedUrl, FixedUrl or RelativeUrl. If it is nil, scheme and host of
+	// `BaseUrl` are used.
+	UrlBuilder UrlBuilder
+	// UpdatedAt is the column of the last update time of items, e.g. the
+	// `updated_at` field of `ent.Timestamps`. If it is set, `GetPage()`
+	// computes the ETag of the page, and returns a *NotModifiedError before
+	// fetching rows if it matches the `If-None-Match` header. It requires the
+	// `MaxOf()` query method generated by the PaginateExtension, and runs
+	// queries sequentially even if `Concurrent`. ETags are not computed if
+	// edges are included, as changes of edges are not tracked by the column.
+	UpdatedAt string
+	// LinksWindow is the number of page links on each side of the current
+	// page in `Links` of the paginated list. `Links` is omitted if it is zero.
+	LinksWindow int
+	// Fields is the whitelist of columns that can be selected by the `fields`
+	// parameter, e.g. the generated `Columns` variable. It requires
+	// `StorageQuery`. Fields of edges are not loaded if their foreign keys
+	// are not selected.
+	Fields []string
+	// IdColumn is the primary key column that is always selected by the
+	// `fields` parameter. Defaults to `id`.
+	IdColumn string
+	// Includes declares the edges that can be eager-loaded by the `include`
+	// parameter.
+	Includes []Include[*Q]
+	// MaxIncludeDepth is the maximum depth of edges in the `include`
+	// parameter. Defaults to `DefaultMaxIncludeDepth`.
+	MaxIncludeDepth int
+	// Snapshot runs the count and the page fetch of `GetPage()` in one
+	// transaction, so they are consistent with each other, e.g. the
+	// generated `Snapshot()` query method. Request dependent clauses,
+	// including eager-loaded edges, are applied in the transaction too.
+	// `Concurrent` is ignored if it is set.
+	Snapshot SnapshotFunc
 }
 
 // PQ is an interface that defines the methods for queries to be paginated.
@@ -42,6 +104,8 @@ type PaginatedParams struct {
 	Page int `form:"page"`
 	// PerPage is the number of items per page.
 	PerPage int `form:"per_page"`
+	// Cursor is the opaque cursor used by keyset pagination.
+	Cursor string `form:"cursor"`
 }
 
 // GetPage returns the current page number.
@@ -64,6 +128,8 @@ func (pp *PaginatedParams) GetPerPage() int {
 type PaginatedList[T any] struct {
 	// Total is the total number of items.
 	Total int `json:"total" bson:"total" xml:"total" yaml:"total"`
+	// TotalExact tells whether Total is exact, or an estimate of `Counter`.
+	TotalExact bool `json:"total_exact" bson:"total_exact" xml:"total_exact" yaml:"total_exact"`
 	// PerPage is the number of items per page.
 	PerPage int `json:"per_page" bson:"per_page" xml:"per_page" yaml:"per_page"`
 	// CurrentPage is the current page number.
@@ -89,10 +155,17 @@ type PaginatedList[T any] struct {
 	To int `json:"to" bson:"to" xml:"to" yaml:"to"`
 	// Data is the list of items.
 	Data []*T `json:"data" bson:"data" xml:"data" yaml:"data"`
+	// Links is the list of numbered page links around the current page, if
+	// `LinksWindow` of the paginator is set.
+	Links []PageLink `json:"links,omitempty" bson:"links,omitempty" xml:"links,omitempty" yaml:"links,omitempty"`
+	// ETag is the entity tag of the page, if `UpdatedAt` of the paginator is
+	// set and no edge is included. It is meant to be sent as a header.
+	ETag string `json:"-" bson:"-" xml:"-" yaml:"-"`
 }
 
 func AttachTo(op *ogen.Operation, description string, itemRef string) {
 	FixParamNames(op.Parameters)
+	SetParamLimits(op.Parameters, Limits{})
 	SetResponse(op, description, itemRef)
 }
 
@@ -102,6 +175,7 @@ func AttachAs(
 	perPageParam string,
 ) {
 	FixParamNamesWith(op.Parameters, pageParam, perPageParam)
+	SetParamLimits(op.Parameters, Limits{})
 	SetResponse(op, "Paginated list of items", itemRef)
 }
 
@@ -152,6 +226,13 @@ func SetResponse(op *ogen.Operation, description string, itemRef string) {
 								Minimum:     ogen.Num("0"),
 							},
 						},
+						{
+							Name: "total_exact",
+							Schema: &ogen.Schema{
+								Type:        "boolean",
+								Description: "Whether the total number of items is exact or an estimate",
+							},
+						},
 						{
 							Name: "per_page",
 							Schema: &ogen.Schema{
@@ -229,10 +310,47 @@ func SetResponse(op *ogen.Operation, description string, itemRef string) {
 								},
 							},
 						},
+						{
+							Name: "links",
+							Schema: &ogen.Schema{
+								Type:        "array",
+								Description: "Numbered page links around the current page",
+								Items: &ogen.Items{
+									Item: &ogen.Schema{
+										Type: "object",
+										Properties: []ogen.Property{
+											{
+												Name: "url",
+												Schema: &ogen.Schema{
+													Type:        "string",
+													Description: "URL to the page, empty for ellipsis",
+												},
+											},
+											{
+												Name: "label",
+												Schema: &ogen.Schema{
+													Type:        "string",
+													Description: "Page number, or `...` for ellipsis",
+												},
+											},
+											{
+												Name: "active",
+												Schema: &ogen.Schema{
+													Type:        "boolean",
+													Description: "Whether it is the current page",
+												},
+											},
+										},
+										Required: []string{"url", "label", "active"},
+									},
+								},
+							},
+						},
 					},
 					Required: []string{
 						"current_page",
 						"total",
+						"total_exact",
 						"per_page",
 						"last_page",
 						"from",
@@ -250,58 +368,54 @@ func SetResponse(op *ogen.Operation, description string, itemRef string) {
 	}
 }
 
-// GetPaginationParams returns the PaginatedParams from the gin.Context,
-// with default values of page `1` and `10` items per page.
-func GetPaginationParams(gc *gin.Context) PaginatedParams {
-	return GetPaginationParamsWithDefault(gc, 1, 10)
-}
-
-// GetPaginationParamsWithDefault returns the PaginatedParams from the
-// gin.Context with given default values.
-func GetPaginationParamsWithDefault(
-	gc *gin.Context, defaultPage, defaultPerPage int,
-) PaginatedParams {
-	var page PaginatedParams
-	if gc.ShouldBind(&page) != nil {
-		page.Page = defaultPage
-		page.PerPage = defaultPerPage
-	}
-	if page.Page < 1 {
-		page.Page = defaultPage
-	}
-	if page.PerPage < 1 {
-		page.PerPage = defaultPerPage
-	}
-	return page
-}
-
 // GetPage returns a paginated list of items. `V` is the type of items in the
 // paginated list. `Q` is the query type to be used to retrieve items, which in
 // most cases can be inferred. So in most cases, only the `V` needs to be
 // provided.
 //
-// The `gc` parameter is the gin.Context to be used to generate various links in
-// the paginated list; `qc` is the context to be used in query execution;
-// `query` is the ent query instance to be executed; and `params` is the
-// PaginatedParams to be used in pagination.
+// The `PageRequest` (or `GinCtx` if it is nil) is used to get pagination
+// parameters and to generate various links in the paginated list; `QueryCtx`
+// is the context to be used in query execution; and `Query` is the ent query
+// instance to be executed.
+//
+// A *ValidationError is returned if pagination parameters are rejected by
+// `Limits`.
 //
 // Please remember to explicitly add the `ORDER` clause to the query before
-// calling this function.
+// calling this function, unless it is sorted by the `sort` parameter.
 func (p Paginator[V, Q]) GetPage() (*PaginatedList[V], error) {
 	var nextUrl, prevUrl string
-	p.params = GetPaginationParams(p.GinCtx)
+	params, err := p.paginationParams()
+	if err != nil {
+		return nil, err
+	}
+	p.params = params
 	firstIdx := 1
 	perPage := p.params.GetPerPage()
 	pageIdx := p.params.GetPage()
 	nextIdx := pageIdx + 1
 	prevIdx := pageIdx - 1
-	count, err := p.Query.Count(p.QueryCtx)
+	var count int
+	var exact bool
+	var etag string
+	var rows []*V
+	if nil == p.Snapshot {
+		if err = p.prepare(); err != nil {
+			return nil, err
+		}
+		count, exact, etag, rows, err = p.countAndFetch(prevIdx*perPage, perPage)
+	} else {
+		count, exact, etag, rows, err = p.snapshotCountAndFetch(
+			prevIdx*perPage, perPage,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
-	if 0 == count {
+	if 0 == count && len(rows) == 0 {
 		return &PaginatedList[V]{
 			Total:        0,
+			TotalExact:   exact,
 			PerPage:      perPage,
 			CurrentPage:  1,
 			LastPage:     1,
@@ -313,15 +427,15 @@ func (p Paginator[V, Q]) GetPage() (*PaginatedList[V], error) {
 			From:         0,
 			To:           0,
 			Data:         []*V{},
+			Links:        p.pageLinks(1, 1, perPage),
+			ETag:         etag,
 		}, nil
 	}
 	from := prevIdx*perPage + 1
 	to := int(math.Min(float64(pageIdx*perPage), float64(count)))
-	p.Query.Offset(prevIdx * perPage)
-	p.Query.Limit(perPage)
-	rows, err := p.Query.All(p.QueryCtx)
-	if err != nil {
-		return nil, err
+	if !exact {
+		// estimated count may be off, so the window is taken from rows
+		to = from + len(rows) - 1
 	}
 	lastIdx := int(math.Ceil(float64(count) / float64(perPage)))
 	firstUrl := p.UrlWithPage(firstIdx, perPage).String()
@@ -346,6 +460,7 @@ func (p Paginator[V, Q]) GetPage() (*PaginatedList[V], error) {
 	}
 	return &PaginatedList[V]{
 		Total:        count,
+		TotalExact:   exact,
 		PerPage:      perPage,
 		CurrentPage:  pageIdx,
 		LastPage:     lastIdx,
@@ -357,39 +472,33 @@ func (p Paginator[V, Q]) GetPage() (*PaginatedList[V], error) {
 		From:         from,
 		To:           to,
 		Data:         rows,
+		Links:        p.pageLinks(pageIdx, lastIdx, perPage),
+		ETag:         etag,
 	}, nil
 }
 
-func (p Paginator[V, Q]) setSchemeHost(url *url.URL) *url.URL {
-	u, err := url.Parse(p.BaseUrl)
-	if err != nil {
-		return url
-	}
-	url.Host = u.Host
-	url.Scheme = u.Scheme
-	return url
-}
-
 // UrlWithPage returns a URL with the page and per_page query parameters set.
 func (p Paginator[V, Q]) UrlWithPage(page int, perPage int) *url.URL {
-	u := utils.RequestUrlWithQueryParams(
-		p.GinCtx.Request, PageQueryParams(page, perPage),
+	u := utils.UrlWithQueryParams(
+		*p.requestUrl(), PageQueryParams(page, perPage),
 	)
-	return p.setSchemeHost(u)
+	return p.buildUrl(u)
 }
 
 // UrlWithoutPageParams returns a URL without the page and per_page query
 // parameters.
 func (p Paginator[V, Q]) UrlWithoutPageParams() *url.URL {
-	u := utils.RequestUrlWithoutQueryParams(
-		p.GinCtx.Request, ParamPage, ParamPerPage,
+	u := utils.UrlWithoutQueryParams(
+		*p.requestUrl(), ParamPage, ParamPerPage,
 	)
-	return p.setSchemeHost(u)
+	return p.buildUrl(u)
 }
 
 // UrlWithoutQuery returns a URL without query string.
 func (p Paginator[V, Q]) UrlWithoutQuery() *url.URL {
-	return p.setSchemeHost(utils.RequestBaseUrl(p.GinCtx.Request))
+	u := *p.requestUrl()
+	u.RawQuery = ""
+	return p.buildUrl(&u)
 }
 
 // PageQueryParams sets the page and per_page query parameters.
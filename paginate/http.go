@@ -0,0 +1,46 @@
+package paginate
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type requestKey struct{}
+
+// NewHttpPageRequest returns the PageRequest of the given http.Request, with
+// default values of page `1` and `10` items per page.
+func NewHttpPageRequest(req *http.Request) *PageRequest {
+	pr := NewPageRequest(req.URL)
+	pr.Request = req
+	return pr
+}
+
+// Middleware returns a net/http middleware that stores the request in the
+// request context, to be retrieved by `RequestFromContext()` and
+// `RequestUrlFromContext()`. It is meant for servers that don't pass the
+// http.Request to handlers, e.g. ogen.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), requestKey{}, r)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		},
+	)
+}
+
+// RequestUrlFromContext returns the request URL stored by `Middleware()`, or
+// nil if there is none.
+func RequestUrlFromContext(ctx context.Context) *url.URL {
+	if req := RequestFromContext(ctx); nil != req {
+		return req.URL
+	}
+	return nil
+}
+
+// RequestFromContext returns the request stored by `Middleware()`, or nil if
+// there is none.
+func RequestFromContext(ctx context.Context) *http.Request {
+	req, _ := ctx.Value(requestKey{}).(*http.Request)
+	return req
+}
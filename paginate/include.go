@@ -0,0 +1,282 @@
+package paginate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-faster/errors"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ogen-go/ogen"
+)
+
+// ParamInclude is the query parameter name for eager-loading edges, e.g.
+// `include=owner,tags.group` loads the `owner` and `tags` edges, and the
+// `group` edge of each tag.
+const ParamInclude = "include"
+
+// DefaultMaxIncludeDepth is the default maximum depth of included edges.
+const DefaultMaxIncludeDepth = 2
+
+// Include declares an edge of queries of type Q that can be eager-loaded by
+// the `include` parameter. It is implemented by IncludeEdge.
+type Include[Q any] interface {
+	includer
+	// queryType binds the declaration to queries of type Q.
+	queryType(Q)
+}
+
+// includer is the type-erased view of Include, used to walk edges of
+// different query types.
+type includer interface {
+	includeName() string
+	nestedIncludes() []includer
+	// includeColumns returns the columns of the query needed to load the
+	// edge, which are fetched regardless of sparse fieldsets.
+	includeColumns() []string
+	// apply eager-loads the edge, and `nested` edges of the edge target.
+	// `query` is always of the query type of the declaration.
+	apply(query any, nested []*IncludePath)
+}
+
+// IncludeEdge declares an edge from queries of type Q to queries of type E,
+// e.g. `IncludeEdge[*ent.PetQuery, *ent.UserQuery]`.
+type IncludeEdge[Q any, E any] struct {
+	// Name is the edge name used in the `include` parameter.
+	Name string
+	// With is the generated method applying the eager-loading of the edge,
+	// e.g. `(*ent.PetQuery).WithOwner`.
+	With func(query Q, opts ...func(E)) Q
+	// Includes are the edges of the edge target that can be included.
+	Includes []Include[E]
+	// Columns are the columns of the query needed to load the edge, e.g. the
+	// generated `pet.ForeignKeys` of the M2O `owner` edge. They are fetched
+	// along with the `fields` parameter.
+	Columns []string
+}
+
+func (e IncludeEdge[Q, E]) queryType(Q) {}
+
+func (e IncludeEdge[Q, E]) includeName() string {
+	return e.Name
+}
+
+func (e IncludeEdge[Q, E]) nestedIncludes() []includer {
+	return includers(e.Includes)
+}
+
+func (e IncludeEdge[Q, E]) includeColumns() []string {
+	return e.Columns
+}
+
+func (e IncludeEdge[Q, E]) apply(query any, nested []*IncludePath) {
+	if len(nested) == 0 {
+		e.With(query.(Q))
+		return
+	}
+	e.With(
+		query.(Q), func(q E) {
+			applyIncludePaths(q, nested)
+		},
+	)
+}
+
+// includers returns the type-erased view of the given declarations.
+func includers[Q any](includes []Include[Q]) []includer {
+	erased := make([]includer, len(includes))
+	for i, include := range includes {
+		erased[i] = include
+	}
+	return erased
+}
+
+// IncludePath is a parsed edge of the `include` parameter, along with its
+// nested edges.
+type IncludePath struct {
+	// Name is the edge name.
+	Name string
+	// Nested is the list of included edges of the edge target.
+	Nested []*IncludePath
+	// include is the declared edge.
+	include includer
+}
+
+// ParseIncludes parses the `include` parameter against the declared edges. A
+// *ValidationError is returned if any edge is not declared, or is nested
+// deeper than `maxDepth`.
+func ParseIncludes[Q any](
+	value string, includes []Include[Q], maxDepth int,
+) ([]*IncludePath, error) {
+	var verr ValidationError
+	var paths []*IncludePath
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if "" == path {
+			continue
+		}
+		names := strings.Split(path, ".")
+		if len(names) > maxDepth {
+			verr.Add(
+				ParamInclude, value,
+				fmt.Sprintf("%s is nested deeper than %d", path, maxDepth),
+			)
+			continue
+		}
+		level, declared := &paths, includers(includes)
+		for _, name := range names {
+			idx := slices.IndexFunc(
+				declared, func(i includer) bool { return i.includeName() == name },
+			)
+			if idx < 0 {
+				verr.Add(ParamInclude, value, "can't include "+path)
+				break
+			}
+			node := findIncludePath(*level, name)
+			if nil == node {
+				node = &IncludePath{Name: name, include: declared[idx]}
+				*level = append(*level, node)
+			}
+			level, declared = &node.Nested, declared[idx].nestedIncludes()
+		}
+	}
+	if err := verr.errorOrNil(); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func findIncludePath(paths []*IncludePath, name string) *IncludePath {
+	for _, path := range paths {
+		if path.Name == name {
+			return path
+		}
+	}
+	return nil
+}
+
+// ApplyIncludes applies the eager-loading of the given edges, which are
+// parsed by `ParseIncludes()` against declarations of the same query type, to
+// the query. Each edge is applied once, before its nested edges.
+func ApplyIncludes[Q any](query Q, paths []*IncludePath) {
+	applyIncludePaths(query, paths)
+}
+
+func applyIncludePaths(query any, paths []*IncludePath) {
+	for _, path := range paths {
+		path.include.apply(query, path.Nested)
+	}
+}
+
+// applyIncludes eager-loads edges of the `include` parameter of the request.
+// It does nothing if no edge is declared or the parameter is absent.
+func (p Paginator[V, Q]) applyIncludes() error {
+	paths, err := p.includePaths()
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	query, ok := any(p.Query).(*Q)
+	if !ok {
+		return errors.Errorf("query %T can't include edges", p.Query)
+	}
+	ApplyIncludes(query, paths)
+	return nil
+}
+
+// includePaths parses the `include` parameter of the request. It returns nil
+// if no edge is declared or the parameter is absent.
+func (p Paginator[V, Q]) includePaths() ([]*IncludePath, error) {
+	if len(p.Includes) == 0 {
+		return nil, nil
+	}
+	value := strings.Join(p.requestUrl().Query()[ParamInclude], ",")
+	if "" == value {
+		return nil, nil
+	}
+	return ParseIncludes(value, p.Includes, p.maxIncludeDepth())
+}
+
+// IncludeColumns returns the columns of the query needed to load the given
+// edges, which must be fetched along with sparse fieldsets.
+func IncludeColumns(paths []*IncludePath) []string {
+	var columns []string
+	for _, path := range paths {
+		for _, column := range path.include.includeColumns() {
+			if !slices.Contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+	}
+	return columns
+}
+
+func (p Paginator[V, Q]) maxIncludeDepth() int {
+	if p.MaxIncludeDepth < 1 {
+		return DefaultMaxIncludeDepth
+	}
+	return p.MaxIncludeDepth
+}
+
+// AttachWithIncludes is like `AttachTo()`, but also adds the `include`
+// parameter of the given edges.
+func AttachWithIncludes[Q any](
+	op *ogen.Operation, description string, itemRef string, maxDepth int,
+	includes ...Include[Q],
+) {
+	AttachTo(op, description, itemRef)
+	AddIncludeParam(op, maxDepth, includes...)
+}
+
+// AddIncludeParam adds the `include` parameter, with the given edges up to
+// `maxDepth`, to the OpenAPI operation.
+func AddIncludeParam[Q any](
+	op *ogen.Operation, maxDepth int, includes ...Include[Q],
+) {
+	op.AddParameters(IncludeParam(maxDepth, includes...))
+}
+
+// IncludeParam returns the `include` query parameter, which is a comma
+// separated list of edge paths, up to `maxDepth` edges each.
+func IncludeParam[Q any](maxDepth int, includes ...Include[Q]) *ogen.Parameter {
+	if maxDepth < 1 {
+		maxDepth = DefaultMaxIncludeDepth
+	}
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	var enum ogen.Enum
+	for _, path := range includePaths("", includers(includes), maxDepth) {
+		name, _ := json.Marshal(path)
+		enum = append(enum, name)
+	}
+	explode := false
+	return &ogen.Parameter{
+		Name: ParamInclude,
+		In:   "query",
+		Description: "Comma separated edges to be loaded, " +
+			"nested edges are separated by `.`",
+		Required: false,
+		Style:    "form",
+		Explode:  &explode,
+		Schema: &ogen.Schema{
+			Type: "array",
+			Items: &ogen.Items{
+				Item: &ogen.Schema{Type: "string", Enum: enum},
+			},
+		},
+	}
+}
+
+// includePaths returns the dotted paths of the given edges and their nested
+// edges, up to `depth` edges.
+func includePaths(prefix string, includes []includer, depth int) []string {
+	if depth < 1 {
+		return nil
+	}
+	var paths []string
+	for _, include := range includes {
+		path := prefix + include.includeName()
+		paths = append(paths, path)
+		paths = append(
+			paths, includePaths(path+".", include.nestedIncludes(), depth-1)...,
+		)
+	}
+	return paths
+}
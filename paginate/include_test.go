@@ -0,0 +1,187 @@
+package paginate
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"testing"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+type testPet struct{}
+
+type testUser struct{}
+
+type testPetQuery struct {
+	owner *testUserQuery
+}
+
+func (q *testPetQuery) Offset(int) *testPetQuery { return q }
+
+func (q *testPetQuery) Limit(int) *testPetQuery { return q }
+
+func (q *testPetQuery) Count(context.Context) (int, error) { return 0, nil }
+
+func (q *testPetQuery) All(context.Context) ([]*testPet, error) {
+	return nil, nil
+}
+
+func (q *testPetQuery) WithOwner(opts ...func(*testUserQuery)) *testPetQuery {
+	q.owner = &testUserQuery{}
+	for _, opt := range opts {
+		opt(q.owner)
+	}
+	return q
+}
+
+type testUserQuery struct {
+	pets *testPetQuery
+}
+
+func (q *testUserQuery) WithPets(opts ...func(*testPetQuery)) *testUserQuery {
+	q.pets = &testPetQuery{}
+	for _, opt := range opts {
+		opt(q.pets)
+	}
+	return q
+}
+
+type testStorageQuery struct {
+	predicates []func(*sql.Selector)
+}
+
+func (q *testStorageQuery) Order(...func(*sql.Selector)) {}
+
+func (q *testStorageQuery) WhereP(ps ...func(*sql.Selector)) {
+	q.predicates = append(q.predicates, ps...)
+}
+
+// columns returns the selected columns after applying the predicates.
+func (q *testStorageQuery) columns() []string {
+	s := sql.Select("*").From(sql.Table("pets"))
+	for _, p := range q.predicates {
+		p(s)
+	}
+	return s.SelectedColumns()
+}
+
+var testIncludes = []Include[*testPetQuery]{
+	IncludeEdge[*testPetQuery, *testUserQuery]{
+		Name:    "owner",
+		With:    (*testPetQuery).WithOwner,
+		Columns: []string{"user_pets"},
+		Includes: []Include[*testUserQuery]{
+			IncludeEdge[*testUserQuery, *testPetQuery]{
+				Name: "pets",
+				With: (*testUserQuery).WithPets,
+			},
+		},
+	},
+}
+
+func TestParseIncludes(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		maxDepth int
+		want     []string
+		invalid  bool
+	}{
+		{"empty", "", 2, nil, false},
+		{"single", "owner", 2, []string{"owner"}, false},
+		{"nested", "owner.pets", 2, []string{"owner.pets"}, false},
+		{"merged", "owner, owner.pets", 2, []string{"owner.pets"}, false},
+		{"too deep", "owner.pets", 1, nil, true},
+		{"deeper than declared", "owner.pets.owner", 3, nil, true},
+		{"unknown", "owner,tags", 2, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				paths, err := ParseIncludes(tt.value, testIncludes, tt.maxDepth)
+				if tt.invalid {
+					var verr *ValidationError
+					if !errors.As(err, &verr) {
+						t.Fatalf("want *ValidationError, got %v", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := flattenIncludes("", paths); !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+// flattenIncludes returns the dotted paths of the leaves of the given edges.
+func flattenIncludes(prefix string, paths []*IncludePath) []string {
+	var flat []string
+	for _, path := range paths {
+		if len(path.Nested) == 0 {
+			flat = append(flat, prefix+path.Name)
+			continue
+		}
+		flat = append(flat, flattenIncludes(prefix+path.Name+".", path.Nested)...)
+	}
+	return flat
+}
+
+func TestApplyIncludes(t *testing.T) {
+	paths, err := ParseIncludes("owner.pets", testIncludes, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := &testPetQuery{}
+	ApplyIncludes(query, paths)
+	if nil == query.owner || nil == query.owner.pets {
+		t.Fatalf("edges are not loaded: %+v", query)
+	}
+}
+
+func TestFieldsWithIncludes(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			"fields only", "fields=name",
+			[]string{"`pets`.`id`", "`pets`.`name`"},
+		},
+		{
+			"foreign keys of included edges", "fields=name&include=owner",
+			[]string{"`pets`.`id`", "`pets`.`name`", "`pets`.`user_pets`"},
+		},
+		{
+			"nested edges", "fields=name&include=owner.pets",
+			[]string{"`pets`.`id`", "`pets`.`name`", "`pets`.`user_pets`"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				u, _ := url.Parse("/pets?" + tt.query)
+				sq := &testStorageQuery{}
+				p := Paginator[testPet, testPetQuery]{
+					Query:        &testPetQuery{},
+					StorageQuery: sq,
+					PageRequest:  NewPageRequest(u),
+					Fields:       []string{"id", "name"},
+					Includes:     testIncludes,
+				}
+				if err := p.applyFields(); err != nil {
+					t.Fatal(err)
+				}
+				if got := sq.columns(); !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,185 @@
+package paginate
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ogen-go/ogen"
+)
+
+// DefaultMaxPerPage is the default maximum number of items per page. It is
+// the same as the default `itemsPerPage` maximum of entoas.
+const DefaultMaxPerPage = 255
+
+// Limits configures how pagination parameters are validated.
+type Limits struct {
+	// DefaultPage is the page number used if none is given. Defaults to `1`.
+	DefaultPage int
+	// DefaultPerPage is the number of items per page used if none is given.
+	// Defaults to `10`.
+	DefaultPerPage int
+	// MaxPerPage is the maximum number of items per page. Defaults to
+	// `DefaultMaxPerPage`.
+	MaxPerPage int
+	// Strict rejects invalid parameters with a *ValidationError. Otherwise,
+	// non-numeric values fall back to defaults, and out-of-range values are
+	// clamped.
+	Strict bool
+}
+
+// InvalidParam describes an invalid request parameter.
+type InvalidParam struct {
+	// Name is the name of the parameter.
+	Name string `json:"name" bson:"name" xml:"name" yaml:"name"`
+	// Value is the value of the parameter as given in the request.
+	Value string `json:"value" bson:"value" xml:"value" yaml:"value"`
+	// Reason describes why the value is invalid.
+	Reason string `json:"reason" bson:"reason" xml:"reason" yaml:"reason"`
+}
+
+// ValidationError is returned when request parameters are invalid. It should
+// be responded with status `400`.
+type ValidationError struct {
+	// Params is the list of invalid parameters.
+	Params []InvalidParam `json:"params" bson:"params" xml:"params" yaml:"params"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Params))
+	for i, p := range e.Params {
+		msgs[i] = fmt.Sprintf("%s=%q: %s", p.Name, p.Value, p.Reason)
+	}
+	return "invalid parameters: " + strings.Join(msgs, "; ")
+}
+
+// Add appends an invalid parameter to the error.
+func (e *ValidationError) Add(name, value, reason string) {
+	e.Params = append(
+		e.Params, InvalidParam{Name: name, Value: value, Reason: reason},
+	)
+}
+
+// errorOrNil returns nil if there is no invalid parameter.
+func (e *ValidationError) errorOrNil() error {
+	if len(e.Params) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (l Limits) defaultPage() int {
+	if l.DefaultPage < 1 {
+		return 1
+	}
+	return l.DefaultPage
+}
+
+func (l Limits) defaultPerPage() int {
+	if l.DefaultPerPage < 1 {
+		return min(10, l.maxPerPage())
+	}
+	return min(l.DefaultPerPage, l.maxPerPage())
+}
+
+func (l Limits) maxPerPage() int {
+	if l.MaxPerPage < 1 {
+		return DefaultMaxPerPage
+	}
+	return l.MaxPerPage
+}
+
+// Clamp returns the given parameters with out-of-range values clamped.
+func (l Limits) Clamp(params PaginatedParams) PaginatedParams {
+	if params.Page < 1 {
+		params.Page = l.defaultPage()
+	}
+	if params.PerPage < 1 {
+		params.PerPage = l.defaultPerPage()
+	} else if params.PerPage > l.maxPerPage() {
+		params.PerPage = l.maxPerPage()
+	}
+	return params
+}
+
+// ParsePaginationParams returns the PaginatedParams from the given query
+// values. In strict mode, a *ValidationError is returned if any parameter is
+// invalid.
+func ParsePaginationParams(
+	values url.Values, limits Limits,
+) (PaginatedParams, error) {
+	var err ValidationError
+	params := PaginatedParams{Cursor: values.Get(ParamCursor)}
+	if v := values.Get(ParamPage); "" != v {
+		page, e := strconv.Atoi(v)
+		if e != nil {
+			err.Add(ParamPage, v, "must be an integer")
+		} else if page < 1 {
+			err.Add(ParamPage, v, "must be at least 1")
+		} else {
+			params.Page = page
+		}
+	}
+	if v := values.Get(ParamPerPage); "" != v {
+		perPage, e := strconv.Atoi(v)
+		if e != nil {
+			err.Add(ParamPerPage, v, "must be an integer")
+		} else if perPage < 1 {
+			err.Add(ParamPerPage, v, "must be at least 1")
+		} else {
+			if perPage > limits.maxPerPage() {
+				err.Add(
+					ParamPerPage, v,
+					fmt.Sprintf("must be at most %d", limits.maxPerPage()),
+				)
+			}
+			params.PerPage = perPage
+		}
+	}
+	if limits.Strict {
+		if e := err.errorOrNil(); e != nil {
+			return PaginatedParams{}, e
+		}
+	}
+	return limits.Clamp(params), nil
+}
+
+// paginationParams returns the pagination parameters to be used by the
+// paginator. If `Limits` is set, parameters are parsed from the request URL
+// accordingly; otherwise, the parameters of the request are clamped to
+// `DefaultMaxPerPage`.
+func (p Paginator[V, Q]) paginationParams() (PaginatedParams, error) {
+	if nil == p.Limits {
+		return Limits{}.Clamp(p.pageRequest().Params), nil
+	}
+	return ParsePaginationParams(p.requestUrl().Query(), *p.Limits)
+}
+
+// SetParamLimits adds the `minimum` and `maximum` constraints of the given
+// limits to the `page` and `per_page` parameters. It must be called after the
+// parameter names are fixed.
+func SetParamLimits(params []*ogen.Parameter, limits Limits) {
+	for _, param := range params {
+		if ParamPerPage != param.Name && ParamPage != param.Name {
+			continue
+		}
+		if nil == param.Schema {
+			param.Schema = &ogen.Schema{Type: "integer"}
+		}
+		param.Schema.Minimum = ogen.Num("1")
+		if ParamPerPage == param.Name {
+			param.Schema.Maximum = ogen.Num(strconv.Itoa(limits.maxPerPage()))
+		}
+	}
+}
+
+// AttachWithLimits is like `AttachTo()`, but uses the constraints of the
+// given limits.
+func AttachWithLimits(
+	op *ogen.Operation, description string, itemRef string, limits Limits,
+) {
+	FixParamNames(op.Parameters)
+	SetParamLimits(op.Parameters, limits)
+	SetResponse(op, description, itemRef)
+}
@@ -0,0 +1,75 @@
+package paginate
+
+import (
+	"strconv"
+)
+
+// Ellipsis is the label of the PageLink standing for skipped pages.
+const Ellipsis = "..."
+
+// PageLink is a numbered link in the page bar.
+type PageLink struct {
+	// Url is the URL of the page. It is an empty string for ellipsis.
+	Url string `json:"url" bson:"url" xml:"url" yaml:"url"`
+	// Label is the page number, or `Ellipsis` for skipped pages.
+	Label string `json:"label" bson:"label" xml:"label" yaml:"label"`
+	// Active tells whether it is the current page.
+	Active bool `json:"active" bson:"active" xml:"active" yaml:"active"`
+}
+
+// PageWindow returns the page numbers to be shown in the page bar, with `0`
+// standing for ellipsis. The first and the last page are always shown, along
+// with `window` pages on each side of the current page, e.g.
+// `1 0 4 5 6 7 8 0 20` for page 6 of 20 with window 2. A single skipped page
+// is shown instead of an ellipsis.
+func PageWindow(current, last, window int) []int {
+	if last < 1 {
+		return nil
+	}
+	current = min(max(current, 1), last)
+	from := max(current-window, 1)
+	to := min(current+window, last)
+	pages := make([]int, 0, to-from+5)
+	if from > 1 {
+		pages = append(pages, 1)
+	}
+	if from == 3 {
+		pages = append(pages, 2)
+	} else if from > 3 {
+		pages = append(pages, 0)
+	}
+	for i := from; i <= to; i++ {
+		pages = append(pages, i)
+	}
+	if to == last-2 {
+		pages = append(pages, last-1)
+	} else if to < last-2 {
+		pages = append(pages, 0)
+	}
+	if to < last {
+		pages = append(pages, last)
+	}
+	return pages
+}
+
+// pageLinks returns the numbered page links around the current page, or nil
+// if `LinksWindow` is not set.
+func (p Paginator[V, Q]) pageLinks(current, last, perPage int) []PageLink {
+	if p.LinksWindow < 1 {
+		return nil
+	}
+	pages := PageWindow(current, last, p.LinksWindow)
+	links := make([]PageLink, len(pages))
+	for i, page := range pages {
+		if 0 == page {
+			links[i] = PageLink{Label: Ellipsis}
+			continue
+		}
+		links[i] = PageLink{
+			Url:    p.UrlWithPage(page, perPage).String(),
+			Label:  strconv.Itoa(page),
+			Active: page == current,
+		}
+	}
+	return links
+}
@@ -0,0 +1,38 @@
+package paginate
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPageWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int
+		last    int
+		window  int
+		want    []int
+	}{
+		{"no page", 1, 0, 2, nil},
+		{"single page", 1, 1, 2, []int{1}},
+		{"middle", 6, 20, 2, []int{1, 0, 4, 5, 6, 7, 8, 0, 20}},
+		{"first page", 1, 20, 2, []int{1, 2, 3, 0, 20}},
+		{"last page", 20, 20, 2, []int{1, 0, 18, 19, 20}},
+		{"single skipped page", 5, 20, 2, []int{1, 2, 3, 4, 5, 6, 7, 0, 20}},
+		{"single skipped page at end", 16, 20, 2, []int{1, 0, 14, 15, 16, 17, 18, 19, 20}},
+		{"window covers all", 3, 5, 5, []int{1, 2, 3, 4, 5}},
+		{"zero window", 6, 20, 0, []int{1, 0, 6, 0, 20}},
+		{"current below range", -1, 20, 1, []int{1, 2, 0, 20}},
+		{"current above range", 30, 20, 1, []int{1, 0, 19, 20}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got := PageWindow(tt.current, tt.last, tt.window)
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
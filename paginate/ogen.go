@@ -0,0 +1,34 @@
+package paginate
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+)
+
+// OptInt is the interface of ogen generated optional integer parameters.
+type OptInt interface {
+	Get() (int, bool)
+}
+
+// NewOgenPageRequest returns the PageRequest of an ogen generated handler,
+// with default values of page `1` and `10` items per page. The request URL
+// is retrieved from the context, so the ogen server must be wrapped by
+// `Middleware()`.
+func NewOgenPageRequest(
+	ctx context.Context, page OptInt, perPage OptInt,
+) (*PageRequest, error) {
+	req := RequestFromContext(ctx)
+	if nil == req {
+		return nil, errors.New("request URL not found, is Middleware used?")
+	}
+	params := PaginatedParams{Page: 1, PerPage: 10}
+	if v, ok := page.Get(); ok && v > 0 {
+		params.Page = v
+	}
+	if v, ok := perPage.Get(); ok && v > 0 {
+		params.PerPage = v
+	}
+	params.Cursor = req.URL.Query().Get(ParamCursor)
+	return &PageRequest{Url: req.URL, Request: req, Params: params}, nil
+}
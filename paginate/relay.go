@@ -0,0 +1,167 @@
+package paginate
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/go-faster/errors"
+)
+
+// ConnectionArgs holds the Relay connection arguments, e.g. from a GraphQL
+// resolver.
+type ConnectionArgs struct {
+	// First is the number of items after `After` to be returned.
+	First *int
+	// After is the cursor of the item to start after.
+	After *string
+	// Last is the number of items before `Before` to be returned.
+	Last *int
+	// Before is the cursor of the item to end before.
+	Before *string
+}
+
+// Connection is the Relay connection of a keyset paginated list.
+type Connection[T any] struct {
+	// Edges is the list of items along with their cursors.
+	Edges []*Edge[T] `json:"edges" bson:"edges" xml:"edges" yaml:"edges"`
+	// PageInfo holds the pagination information.
+	PageInfo PageInfo `json:"pageInfo" bson:"pageInfo" xml:"pageInfo" yaml:"pageInfo"`
+	// TotalCount is the total number of items, regardless of the arguments.
+	TotalCount int `json:"totalCount" bson:"totalCount" xml:"totalCount" yaml:"totalCount"`
+}
+
+// Edge is an item of a Relay connection.
+type Edge[T any] struct {
+	// Node is the item.
+	Node *T `json:"node" bson:"node" xml:"node" yaml:"node"`
+	// Cursor is the opaque cursor pointing to the item.
+	Cursor string `json:"cursor" bson:"cursor" xml:"cursor" yaml:"cursor"`
+}
+
+// PageInfo is the Relay page information of a connection.
+type PageInfo struct {
+	// HasNextPage tells whether there are more items after `EndCursor`.
+	HasNextPage bool `json:"hasNextPage" bson:"hasNextPage" xml:"hasNextPage" yaml:"hasNextPage"`
+	// HasPreviousPage tells whether there are more items before
+	// `StartCursor`.
+	HasPreviousPage bool `json:"hasPreviousPage" bson:"hasPreviousPage" xml:"hasPreviousPage" yaml:"hasPreviousPage"`
+	// StartCursor is the cursor of the first edge, nil if there is no edge.
+	StartCursor *string `json:"startCursor" bson:"startCursor" xml:"startCursor" yaml:"startCursor"`
+	// EndCursor is the cursor of the last edge, nil if there is no edge.
+	EndCursor *string `json:"endCursor" bson:"endCursor" xml:"endCursor" yaml:"endCursor"`
+}
+
+// GetConnection returns the Relay connection of the query with the given
+// arguments. It shares `OrderFields`, `StorageQuery`, `Counter` and `Limits`
+// with `GetCursorPage()`, and cursors of both are interchangeable. `GinCtx`
+// and `PageRequest` are not needed. The total is counted before cursors are
+// applied, so it is the number of all items of the query.
+func (p Paginator[V, Q]) GetConnection(args ConnectionArgs) (
+	*Connection[V], error,
+) {
+	if nil == p.StorageQuery || len(p.OrderFields) == 0 {
+		return nil, errors.New(
+			"keyset pagination requires StorageQuery and OrderFields",
+		)
+	}
+	size, backward, err := p.connectionSize(args)
+	if err != nil {
+		return nil, err
+	}
+	var after, before []any
+	if nil != args.After {
+		if after, _, err = decodeCursor(*args.After, p.OrderFields); err != nil {
+			return nil, err
+		}
+	}
+	if nil != args.Before {
+		if before, _, err = decodeCursor(*args.Before, p.OrderFields); err != nil {
+			return nil, err
+		}
+	}
+	total, _, err := p.count(p.QueryCtx, p.Query)
+	if err != nil {
+		return nil, err
+	}
+	p.StorageQuery.Order(orderTerms(p.OrderFields, backward)...)
+	if nil != after {
+		p.StorageQuery.WhereP(keysetPredicate(p.OrderFields, after, false))
+	}
+	if nil != before {
+		p.StorageQuery.WhereP(keysetPredicate(p.OrderFields, before, true))
+	}
+	p.Query.Limit(size + 1)
+	rows, err := p.Query.All(p.QueryCtx)
+	if err != nil {
+		return nil, err
+	}
+	more := len(rows) > size
+	if more {
+		rows = rows[:size]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+	conn := &Connection[V]{
+		Edges:      make([]*Edge[V], len(rows)),
+		TotalCount: total,
+	}
+	for i, row := range rows {
+		c, err := encodeCursor(p.OrderFields, row, false)
+		if err != nil {
+			return nil, err
+		}
+		conn.Edges[i] = &Edge[V]{Node: row, Cursor: c}
+	}
+	if backward {
+		conn.PageInfo.HasPreviousPage = more
+		conn.PageInfo.HasNextPage = nil != before
+	} else {
+		conn.PageInfo.HasNextPage = more
+		conn.PageInfo.HasPreviousPage = nil != after
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = &conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn, nil
+}
+
+// connectionSize returns the number of items to be fetched, and whether they
+// are fetched backward from the end, validated against `Limits`.
+func (p Paginator[V, Q]) connectionSize(args ConnectionArgs) (
+	int, bool, error,
+) {
+	var limits Limits
+	if nil != p.Limits {
+		limits = *p.Limits
+	}
+	var verr ValidationError
+	if nil != args.First && nil != args.Last {
+		verr.Add("last", strconv.Itoa(*args.Last), "can't be used with first")
+		return 0, false, &verr
+	}
+	name, size := "first", args.First
+	if nil != args.Last {
+		name, size = "last", args.Last
+	}
+	if nil == size {
+		return limits.defaultPerPage(), false, nil
+	}
+	if *size < 0 {
+		verr.Add(name, strconv.Itoa(*size), "must be at least 0")
+		return 0, false, &verr
+	}
+	if *size > limits.maxPerPage() {
+		if limits.Strict {
+			verr.Add(
+				name, strconv.Itoa(*size),
+				fmt.Sprintf("must be at most %d", limits.maxPerPage()),
+			)
+			return 0, false, &verr
+		}
+		return limits.maxPerPage(), "last" == name, nil
+	}
+	return *size, "last" == name, nil
+}
@@ -0,0 +1,192 @@
+package paginate
+
+import (
+	"github.com/ogen-go/ogen"
+)
+
+// SimpleList is a struct that contains the paginated list of items, without
+// the total number of items and the last page.
+type SimpleList[T any] struct {
+	// PerPage is the number of items per page.
+	PerPage int `json:"per_page" bson:"per_page" xml:"per_page" yaml:"per_page"`
+	// CurrentPage is the current page number.
+	CurrentPage int `json:"current_page" bson:"current_page" xml:"current_page" yaml:"current_page"`
+	// HasMore tells whether there are more items after the current page.
+	HasMore bool `json:"has_more" bson:"has_more" xml:"has_more" yaml:"has_more"`
+	// FirstPageUrl is the URL of the first page.
+	FirstPageUrl string `json:"first_page_url" bson:"first_page_url" xml:"first_page_url" yaml:"first_page_url"`
+	// NextPageUrl is the URL of the next page. It is an empty string if there
+	// is no more items.
+	NextPageUrl string `json:"next_page_url" bson:"next_page_url" xml:"next_page_url" yaml:"next_page_url"`
+	// PrevPageUrl is the URL of the previous page. It is an empty string if
+	// the current page is the first page.
+	PrevPageUrl string `json:"prev_page_url" bson:"prev_page_url" xml:"prev_page_url" yaml:"prev_page_url"`
+	// Path is the fully qualified URL without query string.
+	Path string `json:"path" bson:"path" xml:"path" yaml:"path"`
+	// From is the starting 1-based index of the items.
+	From int `json:"from" bson:"from" xml:"from" yaml:"from"`
+	// To is the ending 1-based index of the items.
+	To int `json:"to" bson:"to" xml:"to" yaml:"to"`
+	// Data is the list of items.
+	Data []*T `json:"data" bson:"data" xml:"data" yaml:"data"`
+}
+
+// GetSimplePage returns a paginated list of items without counting the total
+// number of items. It fetches `per_page+1` rows to determine whether there
+// are more items after the current page.
+//
+// Please remember to explicitly add the `ORDER` clause to the query before
+// calling this function, unless it is sorted by the `sort` parameter.
+func (p Paginator[V, Q]) GetSimplePage() (*SimpleList[V], error) {
+	params, err := p.paginationParams()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.prepare(); err != nil {
+		return nil, err
+	}
+	perPage := params.GetPerPage()
+	pageIdx := params.GetPage()
+	p.Query.Offset((pageIdx - 1) * perPage)
+	p.Query.Limit(perPage + 1)
+	rows, err := p.Query.All(p.QueryCtx)
+	if err != nil {
+		return nil, err
+	}
+	list := &SimpleList[V]{
+		PerPage:      perPage,
+		CurrentPage:  pageIdx,
+		FirstPageUrl: p.UrlWithPage(1, perPage).String(),
+		Path:         p.UrlWithoutQuery().String(),
+		Data:         rows,
+	}
+	if len(rows) > perPage {
+		list.Data = rows[:perPage]
+		list.HasMore = true
+		list.NextPageUrl = p.UrlWithPage(pageIdx+1, perPage).String()
+	}
+	if pageIdx > 1 {
+		list.PrevPageUrl = p.UrlWithPage(pageIdx-1, perPage).String()
+	}
+	if len(list.Data) > 0 {
+		list.From = (pageIdx-1)*perPage + 1
+		list.To = list.From + len(list.Data) - 1
+	}
+	return list, nil
+}
+
+// AttachSimpleTo fixes the parameter names and sets the simple paginated
+// response of given OpenAPI operation.
+func AttachSimpleTo(op *ogen.Operation, description string, itemRef string) {
+	FixParamNames(op.Parameters)
+	SetParamLimits(op.Parameters, Limits{})
+	SetSimpleResponse(op, description, itemRef)
+}
+
+// SetSimpleResponse changes the response of given OpenAPI operation to meet
+// the simple paginated response.
+func SetSimpleResponse(
+	op *ogen.Operation, description string, itemRef string,
+) {
+	op.Responses["200"] = &ogen.Response{
+		Description: description,
+		Content: map[string]ogen.Media{
+			"application/json": {
+				Schema: &ogen.Schema{
+					Type: "object",
+					Properties: []ogen.Property{
+						{
+							Name: "current_page",
+							Schema: &ogen.Schema{
+								Type:        "integer",
+								Description: "Page number (1-based)",
+								Minimum:     ogen.Num("1"),
+							},
+						},
+						{
+							Name: "per_page",
+							Schema: &ogen.Schema{
+								Type:        "integer",
+								Description: "Number of items per page",
+								Minimum:     ogen.Num("1"),
+							},
+						},
+						{
+							Name: "has_more",
+							Schema: &ogen.Schema{
+								Type:        "boolean",
+								Description: "Whether there are more items after the current page",
+							},
+						},
+						{
+							Name: "from",
+							Schema: &ogen.Schema{
+								Type:        "integer",
+								Description: "Index (1-based) of the first item in the current page",
+								Minimum:     ogen.Num("0"),
+							},
+						},
+						{
+							Name: "to",
+							Schema: &ogen.Schema{
+								Type:        "integer",
+								Description: "Index (1-based) of the last item in the current page",
+								Minimum:     ogen.Num("0"),
+							},
+						},
+						{
+							Name: "first_page_url",
+							Schema: &ogen.Schema{
+								Type:        "string",
+								Description: "URL to the first page",
+							},
+						},
+						{
+							Name: "next_page_url",
+							Schema: &ogen.Schema{
+								Type:        "string",
+								Description: "URL to the next page",
+							},
+						},
+						{
+							Name: "prev_page_url",
+							Schema: &ogen.Schema{
+								Type:        "string",
+								Description: "URL to the previous page",
+							},
+						},
+						{
+							Name: "path",
+							Schema: &ogen.Schema{
+								Type:        "string",
+								Description: "Base path of the request",
+							},
+						},
+						{
+							Name: "data",
+							Schema: &ogen.Schema{
+								Type:        "array",
+								Description: "List of items",
+								Items: &ogen.Items{
+									Item: &ogen.Schema{Ref: itemRef},
+								},
+							},
+						},
+					},
+					Required: []string{
+						"current_page",
+						"per_page",
+						"has_more",
+						"from",
+						"to",
+						"first_page_url",
+						"next_page_url",
+						"prev_page_url",
+						"path",
+						"data",
+					},
+				},
+			},
+		},
+	}
+}
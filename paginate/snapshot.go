@@ -0,0 +1,33 @@
+package paginate
+
+import (
+	"context"
+)
+
+// SnapshotFunc runs the given function in a transaction that the query is
+// bound to, e.g. the generated `Snapshot()` query method. `fn` is called with
+// the context to be used by queries in the transaction.
+type SnapshotFunc func(ctx context.Context, fn func(context.Context) error) error
+
+// snapshotCountAndFetch is like `countAndFetch()`, but runs statements in the
+// transaction of `Snapshot`. The query is prepared in the transaction, so
+// queries of included edges, which copy the query config when applied, are
+// bound to the transaction too. `Concurrent` is ignored, as a transaction
+// can't be used by multiple statements at the same time.
+func (p Paginator[V, Q]) snapshotCountAndFetch(offset, limit int) (
+	count int, exact bool, etag string, rows []*V, err error,
+) {
+	err = p.Snapshot(
+		p.queryContext(), func(ctx context.Context) error {
+			p.QueryCtx = ctx
+			p.Concurrent = false
+			if e := p.prepare(); e != nil {
+				return e
+			}
+			var e error
+			count, exact, etag, rows, e = p.countAndFetch(offset, limit)
+			return e
+		},
+	)
+	return
+}
@@ -0,0 +1,131 @@
+package paginate
+
+import (
+	"slices"
+	"strings"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/go-faster/errors"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ogen-go/ogen"
+)
+
+// ParamSort is the query parameter name for sorting, e.g.
+// `sort=-created_at,name` sorts by `created_at` descending then by `name`.
+const ParamSort = "sort"
+
+// SortFunc returns the ORDER BY term of a field with given options, like the
+// generated `ByXxx()` functions.
+type SortFunc func(...sql.OrderTermOption) func(*sql.Selector)
+
+// SortBy converts the generated `ByXxx()` order functions to SortFunc, e.g.
+// `paginate.SortBy(user.ByName)`.
+func SortBy[O ~func(*sql.Selector)](
+	fn func(...sql.OrderTermOption) O,
+) SortFunc {
+	return func(opts ...sql.OrderTermOption) func(*sql.Selector) {
+		return fn(opts...)
+	}
+}
+
+// SortField is a parsed field of the `sort` parameter.
+type SortField struct {
+	// Name is the name of the field.
+	Name string
+	// Desc sorts the field in descending order.
+	Desc bool
+}
+
+// ParseSort parses the `sort` parameter against the given allowed field
+// names. A *ValidationError is returned if any field is not allowed.
+func ParseSort(value string, allowed []string) ([]SortField, error) {
+	var verr ValidationError
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		if "" == name || seen[name] {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			verr.Add(ParamSort, value, "can't sort by "+name)
+			continue
+		}
+		seen[name] = true
+		fields = append(fields, SortField{Name: name, Desc: desc})
+	}
+	if err := verr.errorOrNil(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// applySort orders the query by the `sort` parameter of the request. It does
+// nothing if no sort field is allowed or the parameter is absent.
+func (p Paginator[V, Q]) applySort() error {
+	if len(p.Sorts) == 0 {
+		return nil
+	}
+	value := strings.Join(p.requestUrl().Query()[ParamSort], ",")
+	if "" == value {
+		return nil
+	}
+	if nil == p.StorageQuery {
+		return errors.New("sorting requires StorageQuery")
+	}
+	allowed := make([]string, 0, len(p.Sorts))
+	for name := range p.Sorts {
+		allowed = append(allowed, name)
+	}
+	fields, err := ParseSort(value, allowed)
+	if err != nil {
+		return err
+	}
+	terms := make([]func(*sql.Selector), len(fields))
+	for i, field := range fields {
+		if field.Desc {
+			terms[i] = p.Sorts[field.Name](sql.OrderDesc())
+		} else {
+			terms[i] = p.Sorts[field.Name]()
+		}
+	}
+	p.StorageQuery.Order(terms...)
+	return nil
+}
+
+// AddSortParam adds the `sort` parameter, with the given allowed field names,
+// to the OpenAPI operation.
+func AddSortParam(op *ogen.Operation, fields ...string) {
+	op.AddParameters(SortParam(fields...))
+}
+
+// SortParam returns the `sort` query parameter, which is a comma separated
+// list of the given field names, optionally prefixed with `-` to sort in
+// descending order.
+func SortParam(fields ...string) *ogen.Parameter {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	enum := make(ogen.Enum, 0, 2*len(fields))
+	for _, field := range fields {
+		asc, _ := json.Marshal(field)
+		desc, _ := json.Marshal("-" + field)
+		enum = append(enum, asc, desc)
+	}
+	explode := false
+	return &ogen.Parameter{
+		Name: ParamSort,
+		In:   "query",
+		Description: "Comma separated fields to sort by, " +
+			"prefix a field with `-` to sort in descending order",
+		Required: false,
+		Style:    "form",
+		Explode:  &explode,
+		Schema: &ogen.Schema{
+			Type: "array",
+			Items: &ogen.Items{
+				Item: &ogen.Schema{Type: "string", Enum: enum},
+			},
+		},
+	}
+}
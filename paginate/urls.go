@@ -0,0 +1,278 @@
+package paginate
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+// UrlBuilder is an interface that defines the strategy to build the URLs of
+// pagination links from the request URL.
+type UrlBuilder interface {
+	// Build returns the URL of the link to be sent to clients. `u` is a copy
+	// of the request URL with pagination parameters set, and `req` is the
+	// incoming request, which may be nil if the paginator doesn't have it.
+	// Only scheme, host and path should be changed, the query must be kept.
+	Build(u *url.URL, req *http.Request) (*url.URL, error)
+}
+
+// FixedUrl builds absolute links with scheme, host and path prefix of a fixed
+// base URL, e.g. `https://example.com/api/v2`.
+type FixedUrl struct {
+	// Base is the absolute base URL. Its path, if any, is prepended to the
+	// request path.
+	Base string
+}
+
+// Build returns the URL with scheme and host of `Base`, and its path prefixed
+// by the path of `Base`.
+func (b FixedUrl) Build(u *url.URL, _ *http.Request) (*url.URL, error) {
+	base, err := url.Parse(b.Base)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid base URL")
+	}
+	if "" == base.Scheme || "" == base.Host {
+		return nil, errors.Errorf("base URL %q must be absolute", b.Base)
+	}
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	addPathPrefix(u, base.Path)
+	return u, nil
+}
+
+// RelativeUrl builds links without scheme and host, optionally with a path
+// prefix.
+type RelativeUrl struct {
+	// Prefix is prepended to the request path, e.g. `/api/v2`.
+	Prefix string
+}
+
+// Build returns the URL without scheme and host, and its path prefixed by
+// `Prefix`.
+func (b RelativeUrl) Build(u *url.URL, _ *http.Request) (*url.URL, error) {
+	if err := checkPathPrefix(b.Prefix); err != nil {
+		return nil, err
+	}
+	u.Scheme = ""
+	u.Host = ""
+	u.User = nil
+	addPathPrefix(u, b.Prefix)
+	return u, nil
+}
+
+// ForwardedUrl builds absolute links from the `Forwarded` header (RFC 7239),
+// or the `X-Forwarded-Proto`, `X-Forwarded-Host` and `X-Forwarded-Prefix`
+// headers, falling back to the scheme and host of the request. Headers are
+// only used if the request comes from one of `TrustedProxies`, as clients can
+// send them too. Of headers listing several hops, only the last element is
+// used, which is appended by the trusted proxy; elements before it may come
+// from the client.
+type ForwardedUrl struct {
+	// Prefix is prepended to the request path, after the prefix given by the
+	// `X-Forwarded-Prefix` header.
+	Prefix string
+	// TrustedProxies is the list of IP addresses or CIDRs of proxies that are
+	// trusted to set the forwarded headers, e.g. `10.0.0.0/8`. Forwarded
+	// headers are ignored if it is empty.
+	TrustedProxies []string
+}
+
+// Build returns the URL with scheme, host and path prefix of the forwarded
+// headers, if the request comes from a trusted proxy.
+func (b ForwardedUrl) Build(u *url.URL, req *http.Request) (*url.URL, error) {
+	if nil == req {
+		return nil, errors.New("forwarded URL requires the HTTP request")
+	}
+	if err := checkPathPrefix(b.Prefix); err != nil {
+		return nil, err
+	}
+	trusted, err := b.trusted(req)
+	if err != nil {
+		return nil, err
+	}
+	var scheme, host, prefix string
+	if trusted {
+		scheme, host = forwarded(headerValue(req, "Forwarded"))
+		if "" == scheme {
+			scheme = lastValue(headerValue(req, "X-Forwarded-Proto"))
+		}
+		if "" == host {
+			host = lastValue(headerValue(req, "X-Forwarded-Host"))
+		}
+		prefix = lastValue(headerValue(req, "X-Forwarded-Prefix"))
+	}
+	if "" == scheme {
+		scheme = "http"
+		if nil != req.TLS {
+			scheme = "https"
+		}
+	}
+	if "" == host {
+		host = req.Host
+	}
+	if "" == host {
+		return nil, errors.New("can't determine the host of the request")
+	}
+	if "" != prefix && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	u.Scheme = strings.ToLower(scheme)
+	u.Host = host
+	addPathPrefix(u, b.Prefix)
+	addPathPrefix(u, prefix)
+	return u, nil
+}
+
+// trusted tells whether the request comes from one of `TrustedProxies`. An
+// invalid entry of `TrustedProxies` is reported as an error.
+func (b ForwardedUrl) trusted(req *http.Request) (bool, error) {
+	if len(b.TrustedProxies) == 0 {
+		return false, nil
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		// unknown remote address, e.g. a unix socket
+		addr = netip.Addr{}
+	}
+	addr = addr.Unmap()
+	for _, proxy := range b.TrustedProxies {
+		prefix, err := parseProxy(proxy)
+		if err != nil {
+			return false, err
+		}
+		if addr.IsValid() && prefix.Contains(addr) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseProxy parses an IP address or a CIDR of trusted proxies.
+func parseProxy(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return netip.Prefix{}, errors.Wrap(err, "invalid trusted proxy")
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, errors.Wrap(err, "invalid trusted proxy")
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// hostUrl is the default builder, which copies scheme and host of `BaseUrl`
+// and keeps the request path as is.
+type hostUrl struct {
+	base string
+}
+
+func (b hostUrl) Build(u *url.URL, _ *http.Request) (*url.URL, error) {
+	base, err := url.Parse(b.base)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid BaseUrl")
+	}
+	u.Host = base.Host
+	u.Scheme = base.Scheme
+	return u, nil
+}
+
+// headerValue returns all values of the header joined by commas, as proxies
+// may append a header line instead of extending the existing one.
+func headerValue(req *http.Request, name string) string {
+	return strings.Join(req.Header.Values(name), ",")
+}
+
+// forwarded returns the proto and host of the last element of the
+// `Forwarded` header.
+func forwarded(header string) (string, string) {
+	var proto, host string
+	element := lastValue(header)
+	for _, pair := range strings.Split(element, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch strings.ToLower(key) {
+		case "proto":
+			proto = value
+		case "host":
+			host = value
+		}
+	}
+	return proto, host
+}
+
+// lastValue returns the last value of a comma separated header, which is the
+// one set by the proxy nearest to the server.
+func lastValue(header string) string {
+	idx := strings.LastIndex(header, ",")
+	return strings.TrimSpace(header[idx+1:])
+}
+
+func checkPathPrefix(prefix string) error {
+	if "" != prefix && !strings.HasPrefix(prefix, "/") {
+		return errors.Errorf("path prefix %q must start with /", prefix)
+	}
+	return nil
+}
+
+func addPathPrefix(u *url.URL, prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if "" == prefix {
+		return
+	}
+	u.Path = prefix + u.Path
+	if "" != u.RawPath {
+		u.RawPath = prefix + u.RawPath
+	}
+}
+
+// urlBuilder returns `UrlBuilder`, or the default builder using `BaseUrl`.
+func (p Paginator[V, Q]) urlBuilder() UrlBuilder {
+	if nil != p.UrlBuilder {
+		return p.UrlBuilder
+	}
+	return hostUrl{base: p.BaseUrl}
+}
+
+// httpRequest returns the incoming request, or nil if there is none.
+func (p Paginator[V, Q]) httpRequest() *http.Request {
+	if nil != p.PageRequest {
+		return p.PageRequest.Request
+	}
+	if nil != p.GinCtx {
+		return p.GinCtx.Request
+	}
+	return nil
+}
+
+// checkUrlBuilder reports misconfiguration of the URL builder before the
+// query is executed.
+func (p Paginator[V, Q]) checkUrlBuilder() error {
+	u := *p.requestUrl()
+	_, err := p.urlBuilder().Build(&u, p.httpRequest())
+	return err
+}
+
+// buildUrl builds the link URL from the given URL. Errors are reported by
+// `checkUrlBuilder()` beforehand, so the given URL is returned on error.
+func (p Paginator[V, Q]) buildUrl(u *url.URL) *url.URL {
+	built, err := p.urlBuilder().Build(u, p.httpRequest())
+	if err != nil {
+		return u
+	}
+	return built
+}
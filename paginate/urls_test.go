@@ -0,0 +1,105 @@
+package paginate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestForwardedUrl(t *testing.T) {
+	proxies := []string{"10.0.0.0/8"}
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string][]string
+		want    string
+	}{
+		{
+			"untrusted remote", "192.0.2.1:1234",
+			map[string][]string{"Forwarded": {"proto=https;host=evil.com"}},
+			"http://example.com/pets",
+		},
+		{
+			"forwarded", "10.0.0.1:1234",
+			map[string][]string{"Forwarded": {"proto=https;host=api.com"}},
+			"https://api.com/pets",
+		},
+		{
+			"forwarded rightmost element", "10.0.0.1:1234",
+			map[string][]string{
+				"Forwarded": {`proto=http;host=evil.com, proto=https;host="api.com"`},
+			},
+			"https://api.com/pets",
+		},
+		{
+			"forwarded appended line", "10.0.0.1:1234",
+			map[string][]string{
+				"Forwarded": {"proto=http;host=evil.com", "proto=https;host=api.com"},
+			},
+			"https://api.com/pets",
+		},
+		{
+			"x-forwarded", "10.0.0.1:1234",
+			map[string][]string{
+				"X-Forwarded-Proto":  {"https"},
+				"X-Forwarded-Host":   {"api.com"},
+				"X-Forwarded-Prefix": {"/v1"},
+			},
+			"https://api.com/v1/pets",
+		},
+		{
+			"x-forwarded rightmost value", "10.0.0.1:1234",
+			map[string][]string{
+				"X-Forwarded-Proto":  {"http, https"},
+				"X-Forwarded-Host":   {"evil.com, api.com"},
+				"X-Forwarded-Prefix": {"/evil", "/v1"},
+			},
+			"https://api.com/v1/pets",
+		},
+		{
+			"forwarded precedes x-forwarded", "10.0.0.1:1234",
+			map[string][]string{
+				"Forwarded":        {"host=api.com"},
+				"X-Forwarded-Host": {"other.com"},
+			},
+			"http://api.com/pets",
+		},
+		{
+			"ipv4-mapped remote", "[::ffff:10.0.0.1]:1234",
+			map[string][]string{"X-Forwarded-Host": {"api.com"}},
+			"http://api.com/pets",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+				req.Host = "example.com"
+				req.RemoteAddr = tt.remote
+				for name, values := range tt.headers {
+					for _, value := range values {
+						req.Header.Add(name, value)
+					}
+				}
+				u, _ := url.Parse("/pets")
+				got, err := ForwardedUrl{TrustedProxies: proxies}.Build(u, req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.String() != tt.want {
+					t.Errorf("got %s, want %s", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestForwardedUrlInvalidProxy(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	u, _ := url.Parse("/pets")
+	_, err := ForwardedUrl{TrustedProxies: []string{"10.0.0/8"}}.Build(u, req)
+	if nil == err {
+		t.Error("want error of the invalid trusted proxy")
+	}
+}
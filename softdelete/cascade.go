@@ -0,0 +1,100 @@
+package softdelete
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"entgo.io/contrib/entoas"
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+)
+
+// FieldDeletionID holds the column name for the "deletion_id" field.
+const FieldDeletionID = "deletion_id"
+
+// CascadeAnnotation marks an edge whose target rows are soft-deleted and
+// restored along with the rows of the edge owner.
+type CascadeAnnotation struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Name implements the schema.Annotation interface.
+func (CascadeAnnotation) Name() string {
+	return "SoftDeleteCascade"
+}
+
+// Cascade returns the edge annotation that cascades soft deletes and
+// restores to the edge target. Both ends of the edge must use the Mixin and
+// the DeletionIDMixin.
+func Cascade() CascadeAnnotation {
+	return CascadeAnnotation{Enabled: true}
+}
+
+// DeletionIDMixin adds the "deletion_id" field, which tags rows trashed
+// together by a cascading soft delete.
+type DeletionIDMixin struct {
+	mixin.Schema
+}
+
+// Fields of the DeletionIDMixin.
+func (DeletionIDMixin) Fields() []ent.Field {
+	return []ent.Field{
+		field.String(FieldDeletionID).Optional().Nillable().
+			Annotations(entoas.Skip(true)),
+	}
+}
+
+// Cascader is implemented by mutations generated by the SoftDeleteExtension.
+type Cascader interface {
+	// SoftDeleteCascade calls `mutate` to soft-delete, or restore if
+	// `restore` is true, the rows of the mutation, and cascades to the
+	// annotated edges. All statements are run in the same transaction.
+	SoftDeleteCascade(
+		ctx context.Context, restore bool,
+		mutate func(context.Context) (ent.Value, error),
+	) (ent.Value, error)
+}
+
+type deletionIDKey struct{}
+
+// WithDeletionID returns a new context that tags soft-deleted rows with the
+// given deletion ID.
+func WithDeletionID(parent context.Context, id string) context.Context {
+	return context.WithValue(parent, deletionIDKey{}, id)
+}
+
+// DeletionIDFromContext returns the deletion ID of the context, if any.
+func DeletionIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(deletionIDKey{}).(string)
+	return id, ok && "" != id
+}
+
+// NewDeletionID returns a new random deletion ID.
+func NewDeletionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type cascadingKey struct{}
+
+// cascading tells whether the mutation is being saved by its cascade, in
+// which case the hook must not handle it again.
+func cascading(ctx context.Context, m ent.Mutation) bool {
+	return m == ctx.Value(cascadingKey{})
+}
+
+// cascade calls `mutate` through the Cascader of the mutation, if it is
+// implemented.
+func cascade(
+	ctx context.Context, m ent.Mutation, restore bool,
+	mutate func(context.Context) (ent.Value, error),
+) (ent.Value, error) {
+	ctx = context.WithValue(ctx, cascadingKey{}, m)
+	if c, ok := m.(Cascader); ok {
+		return c.SoftDeleteCascade(ctx, restore, mutate)
+	}
+	return mutate(ctx)
+}
@@ -40,7 +40,55 @@ func AttachTo(
 
 // IncludeTrashed returns a new context that skips the soft-delete interceptor/mutators.
 func IncludeTrashed(parent context.Context) context.Context {
-	return context.WithValue(parent, softDeleteKey{}, true)
+	return WithTrashedMode(parent, TrashedWith)
+}
+
+// OnlyTrashed returns a new context that only includes trashed rows, i.e.
+// queries apply `deleted_at IS NOT NULL`. Updates only affect trashed rows,
+// and deletes are rejected, use ForceDelete() to delete them permanently.
+func OnlyTrashed(parent context.Context) context.Context {
+	return WithTrashedMode(parent, TrashedOnly)
+}
+
+// WithTrashedMode returns a new context with the given trashed mode.
+func WithTrashedMode(parent context.Context, mode TrashedMode) context.Context {
+	return context.WithValue(parent, softDeleteKey{}, mode)
+}
+
+// ParseTrashed parses the value of the `trashed` parameter, which is one of
+// the TrashedMode values. Besides, `true` and `false` are accepted for
+// backward compatibility, being TrashedWith and TrashedWithout respectively.
+// Values are case-sensitive, same as the enum of the parameter.
+func ParseTrashed(value string) (TrashedMode, error) {
+	switch mode := TrashedMode(value); mode {
+	case TrashedWithout, TrashedWith, TrashedOnly:
+		return mode, nil
+	}
+	switch value {
+	case "true":
+		return TrashedWith, nil
+	case "false":
+		return TrashedWithout, nil
+	}
+	return "", errors.Errorf(
+		"must be one of %s, %s, %s, true or false", TrashedWithout,
+		TrashedWith, TrashedOnly,
+	)
+}
+
+// Restore returns a new context that turns update mutations into restoring
+// trashed rows, e.g. `client.User.UpdateOneID(id).Exec(softdelete.Restore(ctx))`.
+// Only trashed rows are restored, so restoring a single row that is not
+// trashed fails with the not found error of ent.
+func Restore(parent context.Context) context.Context {
+	return context.WithValue(parent, mutationOpKey{}, opRestore)
+}
+
+// ForceDelete returns a new context that turns delete mutations into real
+// deletes, whether the row is trashed or not, e.g.
+// `client.User.DeleteOneID(id).Exec(softdelete.ForceDelete(ctx))`.
+func ForceDelete(parent context.Context) context.Context {
+	return context.WithValue(parent, mutationOpKey{}, opForceDelete)
 }
 
 // NewSoftDeleteQueryContext returns a new context that includes the soft delete pattern.
@@ -58,6 +106,24 @@ func NewSoftDeleteQueryContext(
 	return ctx
 }
 
+// NewTrashedModeContext is like `NewSoftDeleteQueryContext()`, but sets the
+// trashed mode parsed from the `trashed` parameter by `ParseTrashed`. Nil or
+// invalid values are ignored.
+func NewTrashedModeContext(
+	trashed *string, ctx context.Context,
+) context.Context {
+	if nil == ctx {
+		ctx = context.Background()
+	}
+	if nil == trashed {
+		return ctx
+	}
+	if mode, err := ParseTrashed(*trashed); nil == err {
+		ctx = WithTrashedMode(ctx, mode)
+	}
+	return ctx
+}
+
 // AddDeletedAtField adds the "deleted_at" field to the oas schema
 func AddDeletedAtField(schema *ogen.Schema) {
 	schema.Properties = append(
@@ -109,11 +175,22 @@ func AddRestoreEndpoint(
 
 // TrashedParam returns the `trashed` query parameter
 func TrashedParam() *ogen.Parameter {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	var enum ogen.Enum
+	for _, v := range []string{
+		string(TrashedWithout), string(TrashedWith), string(TrashedOnly),
+		"true", "false",
+	} {
+		marshal, _ := json.Marshal(v)
+		enum = append(enum, marshal)
+	}
 	return &ogen.Parameter{
-		Name:        ParamTrashed,
-		In:          "query",
-		Description: "Whether to include trashed items",
-		Required:    false,
-		Schema:      &ogen.Schema{Type: "boolean"},
+		Name: ParamTrashed,
+		In:   "query",
+		Description: "Whether to exclude (`without`), include (`with`) or " +
+			"only list (`only`) trashed items, " +
+			"`true` and `false` are the same as `with` and `without`",
+		Required: false,
+		Schema:   &ogen.Schema{Type: "string", Enum: enum},
 	}
 }
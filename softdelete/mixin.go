@@ -10,6 +10,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/mixin"
+	"github.com/go-faster/errors"
 )
 
 // FieldDeletedAt holds the column name for the "deleted_at" field.
@@ -17,6 +18,39 @@ const FieldDeletedAt = "deleted_at"
 
 type softDeleteKey struct{}
 
+// TrashedMode tells how queries and mutations treat trashed rows.
+type TrashedMode string
+
+const (
+	// TrashedWithout excludes trashed rows. It is the default mode.
+	TrashedWithout TrashedMode = "without"
+	// TrashedWith includes trashed rows, i.e. the soft delete pattern is
+	// skipped.
+	TrashedWith TrashedMode = "with"
+	// TrashedOnly only includes trashed rows.
+	TrashedOnly TrashedMode = "only"
+)
+
+// trashedMode returns the trashed mode of the context.
+func trashedMode(ctx context.Context) TrashedMode {
+	if mode, ok := ctx.Value(softDeleteKey{}).(TrashedMode); ok {
+		return mode
+	}
+	return TrashedWithout
+}
+
+type mutationOpKey struct{}
+
+// mutationOp is the soft delete specific operation of a mutation.
+type mutationOp int
+
+const (
+	// opRestore restores trashed rows by clearing the "deleted_at" field.
+	opRestore mutationOp = iota + 1
+	// opForceDelete permanently deletes rows regardless of being trashed.
+	opForceDelete
+)
+
 // Mixin implements the soft delete pattern for schemas.
 type Mixin struct {
 	mixin.Schema
@@ -41,14 +75,19 @@ func Interceptor[Q interface{ WhereP(...func(*sql.Selector)) }](
 				func(ctx context.Context, query ent.Query) (
 					ent.Value, error,
 				) {
-					if skip, _ := ctx.Value(softDeleteKey{}).(bool); skip {
+					mode := trashedMode(ctx)
+					if TrashedWith == mode {
 						return next.Query(ctx, query)
 					}
 					q, err := f(query)
 					if err != nil {
 						return nil, err
 					}
-					q.WhereP(sql.FieldIsNull(FieldDeletedAt))
+					if TrashedOnly == mode {
+						q.WhereP(sql.FieldNotNull(FieldDeletedAt))
+					} else {
+						q.WhereP(sql.FieldIsNull(FieldDeletedAt))
+					}
 					return next.Query(ctx, query)
 				},
 			)
@@ -63,7 +102,12 @@ func Mutator[M interface {
 	return func(next ent.Mutator) ent.Mutator {
 		return ent.MutateFunc(
 			func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
-				if skip, _ := ctx.Value(softDeleteKey{}).(bool); skip {
+				if cascading(ctx, m) {
+					return next.Mutate(ctx, m)
+				}
+				op, _ := ctx.Value(mutationOpKey{}).(mutationOp)
+				mode := trashedMode(ctx)
+				if TrashedWith == mode && 0 == op {
 					return next.Mutate(ctx, m)
 				}
 				mx, ok := m.(interface {
@@ -76,6 +120,29 @@ func Mutator[M interface {
 						"unexpected mutation type %T %#v", m, m,
 					)
 				}
+				switch op {
+				case opRestore:
+					return restore[M](ctx, next, m, mx)
+				case opForceDelete:
+					if !mx.Op().Is(ent.OpDelete | ent.OpDeleteOne) {
+						return nil, fmt.Errorf(
+							"force delete requires a delete mutation, got %s",
+							mx.Op(),
+						)
+					}
+					return next.Mutate(ctx, m)
+				}
+				if TrashedOnly == mode {
+					if mx.Op().Is(ent.OpDelete | ent.OpDeleteOne) {
+						return nil, errors.New(
+							"delete is not allowed in the only-trashed " +
+								"mode, use ForceDelete() to delete " +
+								"trashed rows permanently",
+						)
+					}
+					mx.WhereP(sql.FieldNotNull(FieldDeletedAt))
+					return next.Mutate(ctx, m)
+				}
 				mx.WhereP(sql.FieldIsNull(FieldDeletedAt))
 				if mx.Op().Is(ent.OpDelete | ent.OpDeleteOne) {
 					err := m.SetField(FieldDeletedAt, time.Now())
@@ -89,10 +156,49 @@ func Mutator[M interface {
 							"unexpected mutation type %T %#v", m, m,
 						)
 					}
-					return md.Client().Mutate(ctx, m)
+					return cascade(
+						ctx, m, false,
+						func(ctx context.Context) (ent.Value, error) {
+							return md.Client().Mutate(ctx, m)
+						},
+					)
 				}
 				return next.Mutate(ctx, m)
 			},
 		)
 	}
 }
+
+// restore clears the "deleted_at" field of trashed rows only. Restoring a
+// single row that is not trashed fails with the not found error of ent.
+// Mutations implementing Cascader are saved through their client, so the
+// restore runs in the transaction of the cascade.
+func restore[M interface {
+	Mutate(context.Context, ent.Mutation) (ent.Value, error)
+}](
+	ctx context.Context, next ent.Mutator, m ent.Mutation,
+	mx interface {
+		Op() ent.Op
+		WhereP(...func(*sql.Selector))
+	},
+) (ent.Value, error) {
+	if !mx.Op().Is(ent.OpUpdate | ent.OpUpdateOne) {
+		return nil, fmt.Errorf(
+			"restore requires an update mutation, got %s", mx.Op(),
+		)
+	}
+	mx.WhereP(sql.FieldNotNull(FieldDeletedAt))
+	if err := m.ClearField(FieldDeletedAt); err != nil {
+		return nil, err
+	}
+	md, ok := m.(interface{ Client() M })
+	if _, cascader := m.(Cascader); !cascader || !ok {
+		return next.Mutate(ctx, m)
+	}
+	return cascade(
+		ctx, m, true,
+		func(ctx context.Context) (ent.Value, error) {
+			return md.Client().Mutate(ctx, m)
+		},
+	)
+}
@@ -0,0 +1,175 @@
+package softdelete
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultPurgeBatchSize is the default number of rows deleted per statement.
+const DefaultPurgeBatchSize = 500
+
+// RetentionAnnotation declares how long trashed rows of a schema are kept
+// before being purged.
+type RetentionAnnotation struct {
+	// Period is the retention period, in the format of time.ParseDuration().
+	Period string `json:"period"`
+}
+
+// Name implements the schema.Annotation interface.
+func (RetentionAnnotation) Name() string {
+	return "SoftDeleteRetention"
+}
+
+// Retention returns the schema annotation that purges trashed rows after the
+// given period, e.g. `softdelete.Retention(30 * 24 * time.Hour)`. Schemas
+// without the annotation are never purged.
+func Retention(period time.Duration) RetentionAnnotation {
+	return RetentionAnnotation{Period: period.String()}
+}
+
+// PurgeTarget is a table to be purged, which is generated by the
+// SoftDeleteExtension.
+type PurgeTarget struct {
+	// Table is the name of the table.
+	Table string
+	// Retention is the retention period of trashed rows.
+	Retention time.Duration
+	// Count returns the number of rows trashed before `before`.
+	Count func(ctx context.Context, before time.Time) (int, error)
+	// Delete permanently deletes at most `limit` rows trashed before
+	// `before`, and returns the number of deleted rows.
+	Delete func(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// PurgeResult is the purge result of a table.
+type PurgeResult struct {
+	// Table is the name of the table.
+	Table string `json:"table" bson:"table" xml:"table" yaml:"table"`
+	// Before is the time before which trashed rows are purged.
+	Before time.Time `json:"before" bson:"before" xml:"before" yaml:"before"`
+	// Count is the number of purged rows, or rows to be purged in dry-run.
+	Count int `json:"count" bson:"count" xml:"count" yaml:"count"`
+}
+
+// PurgeReport is the report of a purge run.
+type PurgeReport struct {
+	// DryRun tells whether rows were left untouched.
+	DryRun bool `json:"dry_run" bson:"dry_run" xml:"dry_run" yaml:"dry_run"`
+	// Results are the results of every table, in the order of purging.
+	Results []PurgeResult `json:"results" bson:"results" xml:"results" yaml:"results"`
+}
+
+// Total returns the total number of purged rows.
+func (r PurgeReport) Total() int {
+	total := 0
+	for _, result := range r.Results {
+		total += result.Count
+	}
+	return total
+}
+
+// Purger permanently deletes rows trashed longer than the retention period
+// of their schema.
+type Purger struct {
+	// Targets are the tables to be purged, in the order of purging. The
+	// generated `Client.PurgeTargets()` lists tables referencing others
+	// first, so foreign keys are never violated.
+	Targets []PurgeTarget
+	// BatchSize is the maximum number of rows deleted per statement.
+	// DefaultPurgeBatchSize is used if it is not positive.
+	BatchSize int
+	// DryRun reports rows to be purged without deleting them.
+	DryRun bool
+	// Now returns the current time. time.Now() is used if it is nil.
+	Now func() time.Time
+}
+
+// Purge purges every target once. Cancelling the context stops it between
+// batches, and the report of rows purged so far is returned along with the
+// error.
+func (p Purger) Purge(ctx context.Context) (PurgeReport, error) {
+	report := PurgeReport{DryRun: p.DryRun}
+	now := p.now()
+	for _, target := range p.Targets {
+		result := PurgeResult{
+			Table: target.Table, Before: now.Add(-target.Retention),
+		}
+		var err error
+		if p.DryRun {
+			result.Count, err = target.Count(ctx, result.Before)
+		} else {
+			result.Count, err = p.purge(ctx, target, result.Before)
+		}
+		report.Results = append(report.Results, result)
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// Run purges every target at the given interval, until the context is
+// cancelled. It is meant to be run as a background worker, e.g.
+//
+//	go client.Purger().Run(ctx, time.Hour, func(r softdelete.PurgeReport, err error) {
+//		log.Printf("purged %d rows: %v", r.Total(), err)
+//	})
+//
+// `done` is called after each run, if it is not nil. It returns the error of
+// the context once cancelled, or an error if `interval` is not positive.
+func (p Purger) Run(
+	ctx context.Context, interval time.Duration,
+	done func(PurgeReport, error),
+) error {
+	if interval <= 0 {
+		return fmt.Errorf("purge interval must be positive, got %s", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := p.Purge(ctx)
+		if nil != done {
+			done(report, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge deletes rows of the target in batches, until a batch deletes no row.
+// A batch deleting less than the batch size doesn't end the loop, as rows
+// restored while being purged are left out of the batch.
+func (p Purger) purge(
+	ctx context.Context, target PurgeTarget, before time.Time,
+) (int, error) {
+	total := 0
+	size := p.batchSize()
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		n, err := target.Delete(ctx, before, size)
+		total += n
+		if err != nil || 0 == n {
+			return total, err
+		}
+	}
+}
+
+func (p Purger) batchSize() int {
+	if p.BatchSize < 1 {
+		return DefaultPurgeBatchSize
+	}
+	return p.BatchSize
+}
+
+func (p Purger) now() time.Time {
+	if nil == p.Now {
+		return time.Now()
+	}
+	return p.Now()
+}
@@ -0,0 +1,103 @@
+package softdelete
+
+import (
+	"slices"
+
+	atlas "ariga.io/atlas/sql/schema"
+	"entgo.io/contrib/entoas"
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/dialect/sql/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+)
+
+// FieldNotDeleted holds the column name for the "not_deleted" field, which is
+// the generated column of unique indexes on MySQL. It is 1 if the row is not
+// trashed, or NULL otherwise.
+const FieldNotDeleted = "not_deleted"
+
+// notDeletedExpr is the expression of the "not_deleted" generated column.
+const notDeletedExpr = "IF(`" + FieldDeletedAt + "` IS NULL, 1, NULL)"
+
+// UniqueIndex returns the unique index of the given fields, which ignores
+// trashed rows. It is a partial index `WHERE deleted_at IS NULL` on Postgres
+// and SQLite. MySQL doesn't support partial indexes, so the "not_deleted"
+// generated column of the UniqueMixin is added to the index, which never
+// conflicts while being NULL.
+func UniqueIndex(dialectName string, fields ...string) ent.Index {
+	if dialect.MySQL == dialectName {
+		return index.Fields(
+			slices.Concat(fields, []string{FieldNotDeleted})...,
+		).Unique()
+	}
+	return index.Fields(fields...).Unique().
+		Annotations(entsql.IndexWhere(FieldDeletedAt + " IS NULL"))
+}
+
+// UniqueMixin adds unique indexes that ignore trashed rows to schemas using
+// the Mixin, e.g.
+//
+//	softdelete.UniqueMixin{
+//		Dialect: dialect.Postgres,
+//		Unique:  [][]string{{"email"}, {"tenant_id", "name"}},
+//	}
+//
+// On MySQL, it also adds the "not_deleted" field, which must be migrated
+// with `MigrateGeneratedColumns`. The field must never be set by mutations.
+type UniqueMixin struct {
+	mixin.Schema
+	// Dialect is the SQL dialect of the indexes.
+	Dialect string
+	// Unique is the list of field sets, each of which is unique among rows
+	// that are not trashed.
+	Unique [][]string
+}
+
+// Fields of the UniqueMixin.
+func (m UniqueMixin) Fields() []ent.Field {
+	if dialect.MySQL != m.Dialect {
+		return nil
+	}
+	return []ent.Field{
+		field.Int8(FieldNotDeleted).Optional().Nillable().Immutable().
+			Annotations(entoas.Skip(true)),
+	}
+}
+
+// Indexes of the UniqueMixin.
+func (m UniqueMixin) Indexes() []ent.Index {
+	indexes := make([]ent.Index, 0, len(m.Unique))
+	for _, fields := range m.Unique {
+		indexes = append(indexes, UniqueIndex(m.Dialect, fields...))
+	}
+	return indexes
+}
+
+// MigrateGeneratedColumns is the schema.DiffHook that turns the
+// "not_deleted" columns into stored generated columns, e.g.
+//
+//	client.Schema.Create(
+//		ctx, schema.WithDiffHook(softdelete.MigrateGeneratedColumns),
+//	)
+//
+// It does nothing to tables without the column, so it is safe to be used
+// with any dialect.
+func MigrateGeneratedColumns(next schema.Differ) schema.Differ {
+	return schema.DiffFunc(
+		func(current, desired *atlas.Schema) ([]atlas.Change, error) {
+			for _, t := range desired.Tables {
+				if c, ok := t.Column(FieldNotDeleted); ok {
+					c.SetGeneratedExpr(
+						&atlas.GeneratedExpr{
+							Expr: notDeletedExpr, Type: "STORED",
+						},
+					)
+				}
+			}
+			return next.Diff(current, desired)
+		},
+	)
+}